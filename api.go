@@ -1,6 +1,9 @@
 package telebot
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // API is the interface that wraps all basic methods for interacting
 // with Telegram Bot API.
@@ -42,11 +45,14 @@ type API interface {
 	DeleteStickerSet(name string) error
 	DeleteTopic(chat *Chat, topic *Topic) error
 	Download(file *File, localFilename string) error
+	DownloadTo(file *File, w io.Writer) error
 	Edit(msg Editable, what interface{}, opts ...interface{}) (*Message, error)
 	EditCaption(msg Editable, caption string, opts ...interface{}) (*Message, error)
+	EditCaptionProgress(msg Editable, every time.Duration, format func(done, total int64) string) ProgressFunc
 	EditGeneralTopic(chat *Chat, topic *Topic) error
 	EditInviteLink(chat Recipient, link *ChatInviteLink) (*ChatInviteLink, error)
 	EditMedia(msg Editable, media Inputtable, opts ...interface{}) (*Message, error)
+	EditProgress(msg Editable, every time.Duration, format func(done, total int64) string) ProgressFunc
 	EditReplyMarkup(msg Editable, markup *ReplyMarkup) (*Message, error)
 	EditTopic(chat *Chat, topic *Topic) error
 	File(file *File) (io.ReadCloser, error)
@@ -71,6 +77,8 @@ type API interface {
 	React(to Recipient, msg Editable, r Reactions) error
 	RefundStars(to Recipient, chargeID string) error
 	EditUserStarSubscription(user Recipient, chargeID string, isCanceled bool) error
+	RemoveChatVerification(chat *Chat) error
+	RemoveUserVerification(user Recipient) error
 	RemoveWebhook(dropPending ...bool) error
 	ReopenGeneralTopic(chat *Chat) error
 	ReopenTopic(chat *Chat, topic *Topic) error
@@ -91,6 +99,7 @@ type API interface {
 	SetGameScore(user Recipient, msg Editable, score GameHighScore) (*Message, error)
 	SetGroupDescription(chat *Chat, description string) error
 	SetGroupPermissions(chat *Chat, perms Rights) error
+	SetGroupPhoto(chat *Chat, p *Photo) error
 	SetGroupStickerSet(chat *Chat, setName string) error
 	SetGroupTitle(chat *Chat, title string) error
 	SetMenuButton(chat *User, mb interface{}) error
@@ -120,5 +129,7 @@ type API interface {
 	UnpinAllTopicMessages(chat *Chat, topic *Topic) error
 	UploadSticker(to Recipient, format StickerSetFormat, f File) (*File, error)
 	UserBoosts(chat, user Recipient) ([]Boost, error)
+	VerifyChat(chat *Chat, level VerificationLevel, requirements *VerificationRequirements) error
+	VerifyUser(user Recipient, level VerificationLevel, requirements *VerificationRequirements) error
 	Webhook() (*Webhook, error)
 }
@@ -0,0 +1,63 @@
+package telebot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// botOnlyMethods are the exported methods of Bot intentionally left out
+// of API: the ones managing the bot itself rather than calling Bot API.
+var botOnlyMethods = map[string]bool{
+	"Duplicates":      true,
+	"Group":           true,
+	"Handle":          true,
+	"Local":           true,
+	"Logger":          true,
+	"MaxDownloadSize": true,
+	"MaxUploadSize":   true,
+	"MoveTo":          true,
+	"NewContext":      true,
+	"NewMarkup":       true,
+	"OnError":         true,
+	"ProcessContext":  true,
+	"ProcessUpdate":   true,
+	"Redact":          true,
+	"Start":           true,
+	"Stop":            true,
+	"Trigger":         true,
+	"Use":             true,
+}
+
+func TestAPI(t *testing.T) {
+	api := reflect.TypeOf((*API)(nil)).Elem()
+	bot := reflect.TypeOf(&Bot{})
+
+	for i := 0; i < bot.NumMethod(); i++ {
+		m := bot.Method(i)
+		if botOnlyMethods[m.Name] {
+			_, ok := api.MethodByName(m.Name)
+			assert.False(t, ok, "Bot.%s is both in API and botOnlyMethods", m.Name)
+			continue
+		}
+
+		am, ok := api.MethodByName(m.Name)
+		if !assert.True(t, ok, "Bot.%s is missing in API", m.Name) {
+			continue
+		}
+
+		// skip the receiver of the bot's method
+		in := make([]reflect.Type, m.Type.NumIn()-1)
+		for j := range in {
+			in[j] = m.Type.In(j + 1)
+		}
+		out := make([]reflect.Type, m.Type.NumOut())
+		for j := range out {
+			out[j] = m.Type.Out(j)
+		}
+
+		want := reflect.FuncOf(in, out, m.Type.IsVariadic())
+		assert.Equal(t, want, am.Type, "API.%s signature differs from Bot", m.Name)
+	}
+}
@@ -1,17 +1,19 @@
 package telebot
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 )
 
 // NewBot does try to build a Bot with token `token`, which
@@ -22,8 +24,18 @@ func NewBot(pref Settings) (*Bot, error) {
 	}
 
 	client := pref.Client
+	if pref.Transport != nil {
+		if client != nil {
+			return nil, errors.New("telebot: both Client and Transport are set")
+		}
+
+		var err error
+		if client, err = pref.Transport.Client(); err != nil {
+			return nil, err
+		}
+	}
 	if client == nil {
-		client = &http.Client{Timeout: time.Minute}
+		client = &http.Client{Timeout: DefaultTimeout}
 	}
 
 	if pref.URL == "" {
@@ -32,24 +44,43 @@ func NewBot(pref Settings) (*Bot, error) {
 	if pref.Poller == nil {
 		pref.Poller = &LongPoller{}
 	}
-	if pref.OnError == nil {
-		pref.OnError = defaultOnError
+	if pref.Logger == nil {
+		pref.Logger = NewStdLogger(nil, LevelDebug)
+	}
+	if pref.Metrics == nil {
+		pref.Metrics = nopMetrics{}
+	}
+	if pref.Tracer == nil {
+		pref.Tracer = nopTracer{}
 	}
 
 	bot := &Bot{
 		Token:   pref.Token,
 		URL:     pref.URL,
 		Poller:  pref.Poller,
-		onError: pref.OnError,
-
-		Updates:  make(chan Update, pref.Updates),
-		handlers: make(map[string]HandlerFunc),
-		stop:     make(chan chan struct{}),
-
-		synchronous: pref.Synchronous,
-		verbose:     pref.Verbose,
-		parseMode:   pref.ParseMode,
-		client:      client,
+		Updates: make(chan Update, pref.Updates),
+
+		config: config{
+			onError: pref.OnError,
+			logger:  pref.Logger,
+			metrics: pref.Metrics,
+			tracer:  pref.Tracer,
+			limiter: pref.Limiter,
+
+			handlers: make(map[string]HandlerFunc),
+			stop:     make(chan chan struct{}),
+
+			synchronous: pref.Synchronous,
+			verbose:     pref.Verbose,
+			redact:      redactFields(pref.Redact),
+			parseMode:   pref.ParseMode,
+			client:      client,
+			transport:   pref.Transport,
+			dedup:       pref.Dedup,
+			local:       pref.Local,
+			fileCache:   pref.FileCache,
+			retries:     pref.UploadRetries,
+		},
 	}
 
 	if pref.Offline {
@@ -62,29 +93,60 @@ func NewBot(pref Settings) (*Bot, error) {
 		bot.Me = user
 	}
 
+	if bot.onError == nil {
+		bot.onError = bot.logError
+	}
+
 	bot.group = bot.Group()
 	return bot, nil
 }
 
 // Bot represents a separate Telegram bot instance.
 type Bot struct {
+	// duplicates goes first to be 64-bit aligned for atomic operations.
+	duplicates int64
+	// recording is set by the Recorder poller, see decodeUpdate.
+	recording int32
+
 	Me      *User
 	Token   string
 	URL     string
 	Updates chan Update
 	Poller  Poller
+
+	config
+
+	stopMu     sync.RWMutex
+	stopClient chan struct{}
+
+	// ctx and parent are set for the copies of the bot
+	// bound to the updates, see ContextCarrier.
+	ctx    context.Context
+	parent *Bot
+}
+
+// config is the configuration of the bot, which is shared
+// with its copies bound to the updates.
+type config struct {
 	onError func(error, Context)
+	logger  Logger
+	metrics Metrics
+	tracer  Tracer
+	limiter Limiter
 
 	group       *Group
 	handlers    map[string]HandlerFunc
 	synchronous bool
 	verbose     bool
+	redact      map[string]bool
 	parseMode   ParseMode
 	stop        chan chan struct{}
 	client      *http.Client
-
-	stopMu     sync.RWMutex
-	stopClient chan struct{}
+	transport   *Transport
+	dedup       DedupStore
+	local       bool
+	fileCache   FileCache
+	retries     int
 }
 
 // Settings represents a utility struct for passing certain
@@ -107,6 +169,11 @@ type Settings struct {
 	// Use for debugging purposes only.
 	Verbose bool
 
+	// Redact lists the fields of requests and responses, which values
+	// are hidden in Verbose logs, e.g. "phone_number" or "invoice_payload".
+	// The bot token, "secret_token" and "provider_token" are always hidden.
+	Redact []string
+
 	// ParseMode used to set default parse mode of all sent messages.
 	// It attaches to every send, edit or whatever method. You also
 	// will be able to override the default mode by passing a new one.
@@ -114,28 +181,81 @@ type Settings struct {
 
 	// OnError is a callback function that will get called on errors
 	// resulted from the handler. It is used as post-middleware function.
-	// Notice that context can be nil.
+	// Notice that context can be nil. By default, errors are logged.
 	OnError func(error, Context)
 
+	// Logger receives all the records of the bot, including the errors
+	// and Verbose requests. Defaulted to the standard library logger,
+	// see NewStdLogger.
+	Logger Logger
+
+	// Metrics receives the events of the bot and its webhook,
+	// see NewPrometheusMetrics.
+	Metrics Metrics
+
+	// Tracer traces the handled updates and Bot API requests.
+	// By default, nothing is traced.
+	Tracer Tracer
+
+	// Limiter limits the rate of Bot API requests, except getUpdates.
+	// See NewRateLimiter.
+	Limiter Limiter
+
 	// HTTP Client used to make requests to telegram api
 	Client *http.Client
 
+	// Transport configures the proxy, certificates and timeouts
+	// of the requests. It can't be used along with Client.
+	Transport *Transport
+
 	// Offline allows to create a bot without network for testing purposes.
 	Offline bool
+
+	// Local tells the bot it works with a local Bot API server, which
+	// is set by URL. Files are then downloaded right from the server's
+	// file system and uploaded from disk by their paths, and the larger
+	// size limits are applied. See Bot.MoveTo to migrate the bot.
+	Local bool
+
+	// Dedup enables dropping of the updates already processed.
+	// The drops are counted by Bot.Duplicates and reported to Metrics.
+	// See DedupStore and NewMemoryDedup.
+	Dedup DedupStore
+
+	// FileCache enables reusing of the uploaded files. Media sent from
+	// disk or by URL is replaced with the file ID of its previous upload.
+	// See NewMemoryFileCache and NewDiskFileCache.
+	FileCache FileCache
+
+	// UploadRetries is how many times an upload is repeated if the
+	// connection to the server can't be established. The files must
+	// be readable again, see FromBytes, FromFS and FromOpener. Requests
+	// which may have reached the server aren't repeated, so a message
+	// is never sent twice. Disabled by default.
+	UploadRetries int
 }
 
-var defaultOnError = func(err error, c Context) {
-	if c != nil {
-		log.Println(c.Update().ID, err)
-	} else {
-		log.Println(err)
-	}
+// logError is the default OnError callback.
+func (b *Bot) logError(err error, c Context) {
+	b.Logger().Log(LevelError, "handler failed", append(logFields(c), "error", err)...)
 }
 
 func (b *Bot) OnError(err error, c Context) {
+	if b.onError == nil {
+		b.logError(err, c)
+		return
+	}
 	b.onError(err, c)
 }
 
+// Logger returns the logger of the bot.
+func (b *Bot) Logger() Logger {
+	if b.logger == nil {
+		return NewStdLogger(nil, LevelDebug)
+	}
+	return b.logger
+}
+
 func (b *Bot) debug(err error) {
 	if b.verbose {
 		b.OnError(err, nil)
@@ -207,6 +327,10 @@ func (b *Bot) Trigger(endpoint interface{}, c Context) error {
 // Start brings bot into motion by consuming incoming
 // updates (see Bot.Updates channel).
 func (b *Bot) Start() {
+	if b.parent != nil {
+		b.parent.Start()
+		return
+	}
 	if b.Poller == nil {
 		panic("telebot: can't start without a poller")
 	}
@@ -233,9 +357,18 @@ func (b *Bot) Start() {
 		select {
 		// handle incoming updates
 		case upd := <-b.Updates:
+			b.meter().QueueLength(len(b.Updates))
 			b.ProcessUpdate(upd)
 			// call to stop polling
 		case confirm := <-b.stop:
+			// Stop may have come before the client stopper was made
+			b.stopMu.Lock()
+			if b.stopClient != nil {
+				close(b.stopClient)
+				b.stopClient = nil
+			}
+			b.stopMu.Unlock()
+
 			close(stop)
 			<-stopConfirm
 			close(confirm)
@@ -246,6 +379,16 @@ func (b *Bot) Start() {
 
 // Stop gracefully shuts the poller down.
 func (b *Bot) Stop() {
+	b.stopUnless(nil)
+}
+
+// stopUnless stops the bot like Stop does, unless done is closed
+// first, e.g. as Start has returned or isn't going to be called.
+func (b *Bot) stopUnless(done <-chan struct{}) {
+	if b.parent != nil {
+		b.parent.stopUnless(done)
+		return
+	}
 	b.stopMu.Lock()
 	if b.stopClient != nil {
 		close(b.stopClient)
@@ -254,8 +397,11 @@ func (b *Bot) Stop() {
 	b.stopMu.Unlock()
 
 	confirm := make(chan struct{})
-	b.stop <- confirm
-	<-confirm
+	select {
+	case b.stop <- confirm:
+		<-confirm
+	case <-done:
+	}
 }
 
 // NewMarkup simply returns newly created markup instance.
@@ -360,35 +506,31 @@ func (b *Bot) SendAlbum(to Recipient, a Album, opts ...interface{}) ([]Message,
 	}
 
 	sendOpts := b.extractOptions(opts)
-	media := make([]string, len(a))
-	files := make(map[string]File)
 
-	for i, x := range a {
-		repr := x.MediaFile().process(strconv.Itoa(i), files)
-		if repr == "" {
-			return nil, fmt.Errorf("telebot: album entry #%d does not exist", i)
-		}
+	inputs := make([]File, len(a))
+	keys := make([]string, len(a))
 
-		im := x.InputMedia()
-		im.Media = repr
+	hits := make([]bool, len(a))
 
-		if len(sendOpts.Entities) > 0 {
-			im.Entities = sendOpts.Entities
-		} else {
-			im.ParseMode = sendOpts.ParseMode
+	var cached bool
+	for i, x := range a {
+		inputs[i] = *x.MediaFile()
+		keys[i], hits[i] = b.cachedFile(x.MediaType(), &inputs[i])
+		cached = cached || hits[i]
+	}
+
+	data, files, err := b.sendMediaGroup(to, a, inputs, sendOpts)
+	if err != nil && cached && staleFileID(err) {
+		// some of the cached file IDs are no longer valid,
+		// it's unknown which ones, so all of them are evicted
+		for i, x := range a {
+			if hits[i] {
+				b.evictFile(keys[i])
+			}
+			inputs[i] = *x.MediaFile()
 		}
-
-		data, _ := json.Marshal(im)
-		media[i] = string(data)
+		data, files, err = b.sendMediaGroup(to, a, inputs, sendOpts)
 	}
-
-	params := map[string]string{
-		"chat_id": to.Recipient(),
-		"media":   "[" + strings.Join(media, ",") + "]",
-	}
-	b.embedSendOptions(params, sendOpts)
-
-	data, err := b.sendFiles("sendMediaGroup", files, params)
 	if err != nil {
 		return nil, err
 	}
@@ -419,9 +561,48 @@ func (b *Bot) SendAlbum(to Recipient, a Album, opts ...interface{}) ([]Message,
 		a[i].MediaFile().FileID = newID
 	}
 
+	for i, key := range keys {
+		if i < len(resp.Result) {
+			b.cacheFile(key, a[i].MediaType(), resp.Result[i].Media())
+		}
+	}
+
 	return resp.Result, nil
 }
 
+func (b *Bot) sendMediaGroup(to Recipient, a Album, inputs []File, sendOpts *SendOptions) ([]byte, map[string]File, error) {
+	media := make([]string, len(a))
+	files := make(map[string]File)
+
+	for i, x := range a {
+		repr := inputs[i].process(strconv.Itoa(i), files)
+		if repr == "" {
+			return nil, nil, fmt.Errorf("telebot: album entry #%d does not exist", i)
+		}
+
+		im := x.InputMedia()
+		im.Media = repr
+
+		if len(sendOpts.Entities) > 0 {
+			im.Entities = sendOpts.Entities
+		} else {
+			im.ParseMode = sendOpts.ParseMode
+		}
+
+		data, _ := json.Marshal(im)
+		media[i] = string(data)
+	}
+
+	params := map[string]string{
+		"chat_id": to.Recipient(),
+		"media":   "[" + strings.Join(media, ",") + "]",
+	}
+	b.embedSendOptions(params, sendOpts)
+
+	data, err := b.sendFiles("sendMediaGroup", files, params)
+	return data, files, err
+}
+
 // Reply behaves just like Send() with an exception of "reply-to" indicator.
 // This function will panic upon nil Message.
 func (b *Bot) Reply(to *Message, what interface{}, opts ...interface{}) (*Message, error) {
@@ -685,9 +866,9 @@ func (b *Bot) EditMedia(msg Editable, media Inputtable, opts ...interface{}) (*M
 		repr = file.FileID
 	case file.FileURL != "":
 		repr = file.FileURL
-	case file.OnDisk() || file.FileReader != nil:
+	case file.readable():
 		s := file.FileLocal
-		if file.FileReader != nil {
+		if !file.OnDisk() {
 			s = "0"
 		} else if s == thumbName {
 			thumbName = "thumb2"
@@ -947,55 +1128,120 @@ func (b *Bot) FileByID(fileID string) (File, error) {
 }
 
 // Download saves the file from Telegram servers locally.
-// Maximum file size to download is 20 MB.
+// Maximum file size to download is 20 MB. The file is downloaded
+// into a temporary file in the same directory, which replaces
+// localFilename only once the download succeeds.
 func (b *Bot) Download(file *File, localFilename string) error {
-	reader, err := b.File(file)
-	if err != nil {
-		return err
+	// the temporary file is made next to the target, so it's renamed
+	// within the same file system, even for a bare filename
+	dir, base := filepath.Split(localFilename)
+	if dir == "" {
+		dir = "."
 	}
-	defer reader.Close()
-
-	out, err := os.Create(localFilename)
+	out, err := ioutil.TempFile(dir, "."+base+".*.part")
 	if err != nil {
 		return wrapError(err)
 	}
-	defer out.Close()
+	tmp := out.Name()
 
-	_, err = io.Copy(out, reader)
+	err = b.DownloadTo(file, out)
+	if cerr := out.Close(); err == nil && cerr != nil {
+		err = wrapError(cerr)
+	}
+	if err == nil {
+		mode := os.FileMode(0644)
+		if fi, err := os.Stat(localFilename); err == nil {
+			mode = fi.Mode().Perm()
+		}
+		if err = os.Chmod(tmp, mode); err == nil {
+			err = os.Rename(tmp, localFilename)
+		}
+		if err != nil {
+			err = wrapError(err)
+		}
+	}
 	if err != nil {
-		return wrapError(err)
+		os.Remove(tmp)
+		return err
 	}
 
 	file.FileLocal = localFilename
 	return nil
 }
 
+// DownloadTo writes the file from Telegram servers to w.
+//
+// Files exceeding MaxDownloadSize are rejected with ErrFileTooLarge
+// before the transfer starts. If the connection drops, the download is
+// resumed from the last received byte with an HTTP range request.
+// Once done, the number of written bytes is checked against the file
+// size, if it's known, and ErrIncompleteFile is returned on mismatch.
+func (b *Bot) DownloadTo(file *File, w io.Writer) error {
+	f, err := b.fileInfo(file)
+	if err != nil {
+		return err
+	}
+
+	if b.localPath(f) {
+		r, err := b.File(file)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		n, err := io.Copy(w, r)
+		if err != nil {
+			return wrapError(err)
+		}
+		return checkFileSize(f, n)
+	}
+
+	dst := &downloadWriter{w: w}
+	for attempt := 0; ; attempt++ {
+		err := b.downloadFrom(f, dst, file.progress())
+		if err == nil {
+			break
+		}
+		// don't retry when the destination itself fails
+		if dst.err != nil || attempt >= downloadRetries || !resumable(err) {
+			return err
+		}
+		b.debug(err)
+	}
+
+	return checkFileSize(f, dst.n)
+}
+
 // File gets a file from Telegram servers.
+//
+// Files exceeding MaxDownloadSize are rejected with ErrFileTooLarge.
+// Unlike DownloadTo, the returned reader doesn't resume
+// the transfer on errors.
 func (b *Bot) File(file *File) (io.ReadCloser, error) {
-	f, err := b.FileByID(file.FileID)
+	f, err := b.fileInfo(file)
 	if err != nil {
 		return nil, err
 	}
 
-	url := b.URL + "/file/bot" + b.Token + "/" + f.FilePath
-	file.FilePath = f.FilePath // saving file path
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, wrapError(err)
+	// local server gives the absolute path in its file system
+	if b.localPath(f) {
+		r, err := os.Open(f.FilePath)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		return withProgress(r, fileSize(f, r), file.progress()), nil
 	}
 
-	resp, err := b.client.Do(req)
+	resp, err := b.fileResponse(f, 0)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, fmt.Errorf("telebot: expected status 200 but got %s", resp.Status)
+	total := resp.ContentLength
+	if total < 0 && f.FileSize > 0 {
+		total = f.FileSize
 	}
-
-	return resp.Body, nil
+	return withProgress(resp.Body, total, file.progress()), nil
 }
 
 // StopLiveLocation stops broadcasting live message location
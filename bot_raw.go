@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"mime/multipart"
+	"net"
 	"net/http"
+	"net/textproto"
 	"os"
 	"strconv"
 	"strings"
@@ -20,23 +22,34 @@ import (
 // It also handles API errors, so you only need to unwrap
 // result field from json data.
 func (b *Bot) Raw(method string, payload interface{}) ([]byte, error) {
+	ctx, span := b.startSpan(SpanAPI, "method", method)
+	data, status, err := b.raw(ctx, method, payload)
+	span.SetAttributes("status", status)
+	span.End(err)
+	return data, err
+}
+
+// raw makes the request and returns the HTTP status code,
+// which is 0 if no response was received.
+func (b *Bot) raw(ctx context.Context, method string, payload interface{}) ([]byte, int, error) {
 	url := b.URL + "/bot" + b.Token + "/" + method
 
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(payload); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Cancel the request immediately without waiting for the timeout
 	// when bot is about to stop.
 	// This may become important if doing long polling with long timeout.
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := b.withTimeout(ctx, method, false)
 	defer cancel()
 
 	go func() {
-		b.stopMu.RLock()
-		stopCh := b.stopClient
-		b.stopMu.RUnlock()
+		root := b.root()
+		root.stopMu.RLock()
+		stopCh := root.stopClient
+		root.stopMu.RUnlock()
 
 		select {
 		case <-stopCh:
@@ -47,42 +60,69 @@ func (b *Bot) Raw(method string, payload interface{}) ([]byte, error) {
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &buf)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, 0, wrapError(err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	if err := b.wait(ctx, method); err != nil {
+		return nil, 0, err
+	}
+	start := time.Now()
+
 	resp, err := b.client.Do(req)
 	if err != nil {
-		return nil, wrapError(err)
+		b.meter().APIRequest(method, 0, time.Since(start))
+		redactError(err, b.Token)
+		return nil, 0, wrapError(err)
 	}
 	resp.Close = true
 	defer resp.Body.Close()
 
 	data, err := ioutil.ReadAll(resp.Body)
+	latency := time.Since(start)
+	b.meter().APIRequest(method, resp.StatusCode, latency)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, resp.StatusCode, wrapError(err)
 	}
 
 	if b.verbose {
-		verbose(method, payload, data)
+		b.logVerbose(method, payload, data, latency)
 	}
 
 	// returning data as well
-	return data, extractOk(data)
+	return data, resp.StatusCode, b.checkResponse(method, resp.StatusCode, data)
+}
+
+// wait blocks until the limiter allows the request.
+func (b *Bot) wait(ctx context.Context, method string) error {
+	if b.limiter == nil || method == "getUpdates" {
+		return nil
+	}
+	if err := b.limiter.Wait(ctx); err != nil {
+		return wrapError(err)
+	}
+	return nil
 }
 
 func (b *Bot) sendFiles(method string, files map[string]File, params map[string]string) ([]byte, error) {
-	rawFiles := make(map[string]interface{})
+	if b.local {
+		if err := localFiles(files, params); err != nil {
+			return nil, err
+		}
+	}
+
+	rawFiles := make(map[string]File)
+	replayable := true
+
 	for name, f := range files {
 		switch {
 		case f.InCloud():
 			params[name] = f.FileID
 		case f.FileURL != "":
 			params[name] = f.FileURL
-		case f.OnDisk():
-			rawFiles[name] = f.FileLocal
-		case f.FileReader != nil:
-			rawFiles[name] = f.FileReader
+		case f.readable():
+			rawFiles[name] = f
+			replayable = replayable && f.replayable()
 		default:
 			return nil, fmt.Errorf("telebot: file for field %s doesn't exist", name)
 		}
@@ -92,14 +132,63 @@ func (b *Bot) sendFiles(method string, files map[string]File, params map[string]
 		return b.Raw(method, params)
 	}
 
+	for attempt := 0; ; attempt++ {
+		data, err := b.uploadFiles(method, rawFiles, params)
+		if err == nil || !replayable || attempt >= b.retries || !unsent(err) {
+			return data, err
+		}
+		b.debug(err)
+	}
+}
+
+// checkResponse checks the response and reports the flood waits to the metrics.
+func (b *Bot) checkResponse(method string, status int, data []byte) error {
+	err := checkResponse(method, status, data)
+	redactError(err, b.Token)
+
+	var floodErr FloodError
+	if errors.As(err, &floodErr) {
+		b.meter().FloodWait(method, time.Duration(floodErr.RetryAfter)*time.Second)
+	}
+	return err
+}
+
+// unsent reports whether the request failed before it was sent,
+// as the connection to the server couldn't be established.
+func unsent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (b *Bot) uploadFiles(method string, files map[string]File, params map[string]string) ([]byte, error) {
+	ctx, span := b.startSpan(SpanAPI, "method", method)
+	data, status, err := b.upload(ctx, method, files, params)
+	span.SetAttributes("status", status)
+	span.End(err)
+	return data, err
+}
+
+func (b *Bot) upload(ctx context.Context, method string, files map[string]File, params map[string]string) ([]byte, int, error) {
+	ctx, cancel := b.withTimeout(ctx, method, true)
+	defer cancel()
+
 	pipeReader, pipeWriter := io.Pipe()
 	writer := multipart.NewWriter(pipeWriter)
 
+	// the files must not be read anymore once it's returned,
+	// e.g. by a retry opening them again
+	written := make(chan struct{})
+	defer func() {
+		pipeReader.Close()
+		<-written
+	}()
+
 	go func() {
+		defer close(written)
 		defer pipeWriter.Close()
 
-		for field, file := range rawFiles {
-			if err := addFileToWriter(writer, files[field].fileName, field, file); err != nil {
+		for field, file := range files {
+			if err := addFileToWriter(writer, file, field); err != nil {
 				pipeWriter.CloseWithError(err)
 				return
 			}
@@ -118,43 +207,82 @@ func (b *Bot) sendFiles(method string, files map[string]File, params map[string]
 
 	url := b.URL + "/bot" + b.Token + "/" + method
 
-	resp, err := b.client.Post(url, writer.FormDataContentType(), pipeReader)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pipeReader)
+	if err != nil {
+		pipeReader.CloseWithError(err)
+		return nil, 0, wrapError(err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	if err := b.wait(ctx, method); err != nil {
+		pipeReader.CloseWithError(err)
+		return nil, 0, err
+	}
+	start := time.Now()
+
+	resp, err := b.client.Do(req)
 	if err != nil {
+		b.meter().APIRequest(method, 0, time.Since(start))
+		redactError(err, b.Token)
 		err = wrapError(err)
 		pipeReader.CloseWithError(err)
-		return nil, err
+		return nil, 0, err
 	}
 	resp.Close = true
 	defer resp.Body.Close()
 
+	data, err := ioutil.ReadAll(resp.Body)
+	b.meter().APIRequest(method, resp.StatusCode, time.Since(start))
+
 	if resp.StatusCode == http.StatusInternalServerError {
-		return nil, ErrInternal
+		return nil, resp.StatusCode, ErrInternal
 	}
-
-	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, wrapError(err)
+		return nil, resp.StatusCode, wrapError(err)
 	}
 
-	return data, extractOk(data)
+	return data, resp.StatusCode, b.checkResponse(method, resp.StatusCode, data)
 }
 
-func addFileToWriter(writer *multipart.Writer, filename, field string, file interface{}) error {
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func addFileToWriter(writer *multipart.Writer, f File, field string) error {
 	var reader io.Reader
-	if r, ok := file.(io.Reader); ok {
-		reader = r
-	} else if path, ok := file.(string); ok {
-		f, err := os.Open(path)
+	switch {
+	case f.OnDisk():
+		file, err := os.Open(f.FileLocal)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		reader = file
+	case f.opener() != nil:
+		rc, err := f.opener()()
 		if err != nil {
 			return err
 		}
-		defer f.Close()
-		reader = f
-	} else {
-		return fmt.Errorf("telebot: file for field %v should be io.ReadCloser or string", field)
+		defer rc.Close()
+		reader = rc
+	case f.FileReader != nil:
+		reader = f.FileReader
+	default:
+		return fmt.Errorf("telebot: file for field %v doesn't exist", field)
 	}
 
-	part, err := writer.CreateFormFile(field, filename)
+	if progress := f.progress(); progress != nil {
+		reader = &progressReader{
+			Reader:   reader,
+			total:    fileSize(f, reader),
+			progress: progress,
+		}
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(field), quoteEscaper.Replace(f.uploadName())))
+	h.Set("Content-Type", f.uploadMIME())
+
+	part, err := writer.CreatePart(h)
 	if err != nil {
 		return err
 	}
@@ -169,7 +297,7 @@ func (f *File) process(name string, files map[string]File) string {
 		return f.FileID
 	case f.FileURL != "":
 		return f.FileURL
-	case f.OnDisk() || f.FileReader != nil:
+	case f.readable():
 		files[name] = *f
 		return "attach://" + name
 	}
@@ -195,21 +323,38 @@ func (b *Bot) sendMedia(media Media, params map[string]string, files map[string]
 	kind := media.MediaType()
 	what := "send" + strings.Title(kind)
 
-	if kind == "videoNote" {
-		kind = "video_note"
+	file := *media.MediaFile()
+	key, cached := b.cachedFile(kind, &file)
+
+	field := kind
+	if field == "videoNote" {
+		field = "video_note"
 	}
 
-	sendFiles := map[string]File{kind: *media.MediaFile()}
+	sendFiles := map[string]File{field: file}
 	for k, v := range files {
 		sendFiles[k] = v
 	}
 
 	data, err := b.sendFiles(what, sendFiles, params)
+	if err != nil && cached && staleFileID(err) {
+		// the cached file ID is no longer valid
+		b.evictFile(key)
+		delete(params, field)
+		sendFiles[field] = *media.MediaFile()
+		data, err = b.sendFiles(what, sendFiles, params)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return extractMessage(data)
+	msg, err := extractMessage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	b.cacheFile(key, kind, msg.Media())
+	return msg, nil
 }
 
 func (b *Bot) getMe() (*User, error) {
@@ -246,12 +391,19 @@ func (b *Bot) getUpdates(offset, limit int, timeout time.Duration, allowed []str
 	}
 
 	var resp struct {
-		Result []Update
+		Result []json.RawMessage
 	}
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, wrapError(err)
 	}
-	return resp.Result, nil
+
+	updates := make([]Update, len(resp.Result))
+	for i, data := range resp.Result {
+		if err := b.decodeUpdate(data, &updates[i]); err != nil {
+			return nil, wrapError(err)
+		}
+	}
+	return updates, nil
 }
 
 func (b *Bot) forwardCopyMany(to Recipient, msgs []Editable, key string, opts ...*SendOptions) ([]Message, error) {
@@ -286,8 +438,11 @@ func (b *Bot) forwardCopyMany(to Recipient, msgs []Editable, key string, opts ..
 }
 
 // extractOk checks given result for error. If result is ok returns nil.
-// In other cases it extracts API error. If error is not presented
-// in errors.go, it will be prefixed with `unknown` keyword.
+// In other cases it extracts API error. Known descriptions result in the
+// errors of errors.go themselves, so they can be compared with ==, the
+// flood and migration errors in FloodError and GroupError. The unknown
+// errors carry the response parameters. A body which isn't a Bot API
+// response results in TransportError.
 func extractOk(data []byte) error {
 	var e struct {
 		Ok          bool                   `json:"ok"`
@@ -295,46 +450,40 @@ func extractOk(data []byte) error {
 		Description string                 `json:"description"`
 		Parameters  map[string]interface{} `json:"parameters"`
 	}
-	if json.NewDecoder(bytes.NewReader(data)).Decode(&e) != nil {
-		return nil // FIXME
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return newTransportError(data, err)
 	}
 	if e.Ok {
 		return nil
 	}
-
-	err := Err(e.Description)
-	switch err {
-	case nil:
-	case ErrGroupMigrated:
-		migratedTo, ok := e.Parameters["migrate_to_chat_id"]
-		if !ok {
-			return NewError(e.Code, e.Description)
-		}
-
-		return GroupError{
-			err:        err.(*Error),
-			MigratedTo: int64(migratedTo.(float64)),
-		}
-	default:
-		return err
+	if e.Code == 0 && e.Description == "" {
+		// some JSON, but not the one of Bot API
+		return newTransportError(data, nil)
 	}
 
-	switch e.Code {
-	case http.StatusTooManyRequests:
-		retryAfter, ok := e.Parameters["retry_after"]
-		if !ok {
-			return NewError(e.Code, e.Description)
-		}
+	// unknown errors are shown with the full description
+	apiErr := &Error{
+		Code:        e.Code,
+		Description: e.Description,
+		Message:     e.Description,
+		Parameters:  e.Parameters,
+	}
 
-		err = FloodError{
-			err:        NewError(e.Code, e.Description),
-			RetryAfter: int(retryAfter.(float64)),
-		}
-	default:
-		err = fmt.Errorf("telegram: %s (%d)", e.Description, e.Code)
+	// known errors are copies of the predefined ones,
+	// so they still match them with errors.Is
+	known, _ := Err(e.Description).(*Error)
+	if known != nil {
+		*apiErr = *known
+		apiErr.Parameters = e.Parameters
 	}
 
-	return err
+	switch {
+	case known == ErrGroupMigrated && apiErr.MigratedTo() != 0:
+		return GroupError{err: apiErr, MigratedTo: apiErr.MigratedTo()}
+	case apiErr.RetryAfter() > 0:
+		return FloodError{err: apiErr, RetryAfter: apiErr.RetryAfter()}
+	}
+	return apiErr
 }
 
 // extractMessage extracts common Message result from given data.
@@ -358,20 +507,19 @@ func extractMessage(data []byte) (*Message, error) {
 	return resp.Result, nil
 }
 
-func verbose(method string, payload interface{}, data []byte) {
+func (b *Bot) logVerbose(method string, payload interface{}, data []byte, latency time.Duration) {
 	body, _ := json.Marshal(payload)
+	body = redactJSON(body, b.redact)
 	body = bytes.ReplaceAll(body, []byte(`\"`), []byte(`"`))
 	body = bytes.ReplaceAll(body, []byte(`"{`), []byte(`{`))
 	body = bytes.ReplaceAll(body, []byte(`}"`), []byte(`}`))
 
-	indent := func(b []byte) string {
-		var buf bytes.Buffer
-		json.Indent(&buf, b, "", "  ")
-		return buf.String()
-	}
+	data = redactJSON(data, b.redact)
 
-	log.Printf(
-		"[verbose] telebot: sent request\nMethod: %v\nParams: %v\nResponse: %v",
-		method, indent(body), indent(data),
+	b.Logger().Log(LevelDebug, "sent request",
+		"method", method,
+		"latency", latency,
+		"params", redactToken(string(body), b.Token),
+		"response", redactToken(string(data), b.Token),
 	)
 }
@@ -84,7 +84,11 @@ func TestExtractOk(t *testing.T) {
 		"error_code": 400,
 		"description": "Bad Request: reply message not found"
 	}`)
-	assert.EqualError(t, extractOk(data), ErrNotFoundToReply.Error())
+	assert.Equal(t, &Error{
+		Code:        400,
+		Description: ErrNotFoundToReply.Description,
+	}, extractOk(data))
+	assert.ErrorIs(t, extractOk(data), ErrNotFoundToReply)
 
 	data = []byte(`{
 		"ok": false,
@@ -92,10 +96,17 @@ func TestExtractOk(t *testing.T) {
 		"description": "Too Many Requests: retry after 8",
 		"parameters": {"retry_after": 8}
 	}`)
+	desc := "Too Many Requests: retry after 8"
 	assert.Equal(t, FloodError{
-		err:        NewError(429, "Too Many Requests: retry after 8"),
+		err: &Error{
+			Code:        429,
+			Description: desc,
+			Message:     desc,
+			Parameters:  map[string]interface{}{"retry_after": 8.0},
+		},
 		RetryAfter: 8,
 	}, extractOk(data))
+	assert.True(t, IsRetryable(extractOk(data)))
 
 	data = []byte(`{
 		"ok": false,
@@ -104,9 +115,17 @@ func TestExtractOk(t *testing.T) {
 		"parameters": {"migrate_to_chat_id": -100123456789}
 	}`)
 	assert.Equal(t, GroupError{
-		err:        ErrGroupMigrated,
+		err: &Error{
+			Code:        400,
+			Description: ErrGroupMigrated.Description,
+			Parameters:  map[string]interface{}{"migrate_to_chat_id": -100123456789.0},
+		},
 		MigratedTo: -100123456789,
 	}, extractOk(data))
+	assert.ErrorIs(t, extractOk(data), ErrGroupMigrated)
+
+	var groupErr GroupError
+	assert.False(t, errors.As(extractOk([]byte(`{"ok":false,"error_code":400}`)), &groupErr))
 }
 
 func TestExtractMessage(t *testing.T) {
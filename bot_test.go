@@ -657,7 +657,7 @@ func TestBot(t *testing.T) {
 
 		fwd.ID += 1 // nonexistent message
 		_, err = b.Forward(to, fwd)
-		assert.Equal(t, ErrNotFoundToForward, err)
+		assert.ErrorIs(t, err, ErrNotFoundToForward)
 	})
 
 	t.Run("Edit(what=string)", func(t *testing.T) {
@@ -696,7 +696,7 @@ func TestBot(t *testing.T) {
 		assert.Nil(t, edited.ReplyMarkup)
 
 		_, err = b.Edit(edited, bad)
-		assert.Equal(t, ErrBadButtonData, err)
+		assert.ErrorIs(t, err, ErrBadButtonData)
 	})
 
 	t.Run("Edit(what=Location)", func(t *testing.T) {
@@ -1,6 +1,7 @@
 package telebot
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"sync"
@@ -188,11 +189,33 @@ type Context interface {
 	Set(key string, val interface{})
 }
 
+// ContextCarrier is implemented by the contexts carrying a context.Context,
+// as the native one does. If Settings.Tracer is set, it carries the span
+// of the update, so the Bot API requests made through the context become
+// its children.
+//
+// Usage:
+//
+//	if cc, ok := c.(tele.ContextCarrier); ok {
+//		ctx := cc.Context()
+//		...
+//	}
+type ContextCarrier interface {
+	// Context returns the context.Context of the update.
+	Context() context.Context
+
+	// SetContext replaces the context.Context of the update. The Bot API
+	// requests made through the context are then made within ctx.
+	SetContext(ctx context.Context)
+}
+
 // nativeContext is a native implementation of the Context interface.
 // "context" is taken by context package, maybe there is a better name.
 type nativeContext struct {
 	b     API
 	u     Update
+	ctx   context.Context
+	bound *Bot // the bot making the requests within ctx
 	lock  sync.RWMutex
 	store map[string]interface{}
 }
@@ -444,10 +467,28 @@ func (c *nativeContext) ThreadID() int {
 
 func (c *nativeContext) Send(what interface{}, opts ...interface{}) error {
 	opts = c.inheritOpts(opts...)
-	_, err := c.b.Send(c.Recipient(), what, opts...)
+	if text, ok := what.(string); ok {
+		if b, ok := c.webhookReply(); ok {
+			return b.replyText(c.u.reply, c.Recipient(), text, b.extractOptions(opts))
+		}
+	}
+	_, err := c.api().Send(c.Recipient(), what, opts...)
 	return err
 }
 
+// webhookReply claims the webhook response of the update, if there is one.
+// Only the native bot is able to answer in the webhook response.
+func (c *nativeContext) webhookReply() (*Bot, bool) {
+	if c.u.reply == nil {
+		return nil, false
+	}
+	b, ok := c.b.(*Bot)
+	if !ok || !c.u.reply.claim() {
+		return nil, false
+	}
+	return b, true
+}
+
 func (c *nativeContext) inheritOpts(opts ...interface{}) []interface{} {
 	var (
 		ignoreThread bool
@@ -479,7 +520,7 @@ func (c *nativeContext) inheritOpts(opts ...interface{}) []interface{} {
 func (c *nativeContext) SendAlbum(a Album, opts ...interface{}) error {
 	opts = c.inheritOpts(opts...)
 
-	_, err := c.b.SendAlbum(c.Recipient(), a, opts...)
+	_, err := c.api().SendAlbum(c.Recipient(), a, opts...)
 	return err
 }
 
@@ -489,12 +530,19 @@ func (c *nativeContext) Reply(what interface{}, opts ...interface{}) error {
 		return ErrBadContext
 	}
 	opts = c.inheritOpts(opts...)
-	_, err := c.b.Reply(msg, what, opts...)
+	if text, ok := what.(string); ok {
+		if b, ok := c.webhookReply(); ok {
+			sendOpts := b.extractOptions(opts)
+			sendOpts.ReplyTo = msg
+			return b.replyText(c.u.reply, msg.Chat, text, sendOpts)
+		}
+	}
+	_, err := c.api().Reply(msg, what, opts...)
 	return err
 }
 
 func (c *nativeContext) Forward(msg Editable, opts ...interface{}) error {
-	_, err := c.b.Forward(c.Recipient(), msg, opts...)
+	_, err := c.api().Forward(c.Recipient(), msg, opts...)
 	return err
 }
 
@@ -503,7 +551,7 @@ func (c *nativeContext) ForwardTo(to Recipient, opts ...interface{}) error {
 	if msg == nil {
 		return ErrBadContext
 	}
-	_, err := c.b.Forward(to, msg, opts...)
+	_, err := c.api().Forward(to, msg, opts...)
 	return err
 }
 
@@ -511,11 +559,11 @@ func (c *nativeContext) Edit(what interface{}, opts ...interface{}) error {
 	opts = c.inheritOpts(opts...)
 
 	if c.u.InlineResult != nil {
-		_, err := c.b.Edit(c.u.InlineResult, what, opts...)
+		_, err := c.api().Edit(c.u.InlineResult, what, opts...)
 		return err
 	}
 	if c.u.Callback != nil {
-		_, err := c.b.Edit(c.u.Callback, what, opts...)
+		_, err := c.api().Edit(c.u.Callback, what, opts...)
 		return err
 	}
 	return ErrBadContext
@@ -525,11 +573,11 @@ func (c *nativeContext) EditCaption(caption string, opts ...interface{}) error {
 	opts = c.inheritOpts(opts...)
 
 	if c.u.InlineResult != nil {
-		_, err := c.b.EditCaption(c.u.InlineResult, caption, opts...)
+		_, err := c.api().EditCaption(c.u.InlineResult, caption, opts...)
 		return err
 	}
 	if c.u.Callback != nil {
-		_, err := c.b.EditCaption(c.u.Callback, caption, opts...)
+		_, err := c.api().EditCaption(c.u.Callback, caption, opts...)
 		return err
 	}
 	return ErrBadContext
@@ -556,7 +604,7 @@ func (c *nativeContext) Delete() error {
 	if msg == nil {
 		return ErrBadContext
 	}
-	return c.b.Delete(msg)
+	return c.api().Delete(msg)
 }
 
 func (c *nativeContext) DeleteAfter(d time.Duration) *time.Timer {
@@ -570,28 +618,31 @@ func (c *nativeContext) DeleteAfter(d time.Duration) *time.Timer {
 }
 
 func (c *nativeContext) Notify(action ChatAction) error {
-	return c.b.Notify(c.Recipient(), action, c.ThreadID())
+	return c.api().Notify(c.Recipient(), action, c.ThreadID())
 }
 
 func (c *nativeContext) Ship(what ...interface{}) error {
 	if c.u.ShippingQuery == nil {
 		return errors.New("telebot: context shipping query is nil")
 	}
-	return c.b.Ship(c.u.ShippingQuery, what...)
+	return c.api().Ship(c.u.ShippingQuery, what...)
 }
 
 func (c *nativeContext) Accept(errorMessage ...string) error {
 	if c.u.PreCheckoutQuery == nil {
 		return errors.New("telebot: context pre checkout query is nil")
 	}
-	return c.b.Accept(c.u.PreCheckoutQuery, errorMessage...)
+	return c.api().Accept(c.u.PreCheckoutQuery, errorMessage...)
 }
 
 func (c *nativeContext) Respond(resp ...*CallbackResponse) error {
 	if c.u.Callback == nil {
 		return errors.New("telebot: context callback is nil")
 	}
-	return c.b.Respond(c.u.Callback, resp...)
+	if b, ok := c.webhookReply(); ok {
+		return b.replyRespond(c.u.reply, c.u.Callback, resp...)
+	}
+	return c.api().Respond(c.u.Callback, resp...)
 }
 
 func (c *nativeContext) RespondText(text string) error {
@@ -606,7 +657,7 @@ func (c *nativeContext) Answer(resp *QueryResponse) error {
 	if c.u.Query == nil {
 		return errors.New("telebot: context inline query is nil")
 	}
-	return c.b.Answer(c.u.Query, resp)
+	return c.api().Answer(c.u.Query, resp)
 }
 
 func (c *nativeContext) Set(key string, value interface{}) {
@@ -625,3 +676,34 @@ func (c *nativeContext) Get(key string) interface{} {
 	defer c.lock.RUnlock()
 	return c.store[key]
 }
+
+func (c *nativeContext) Context() context.Context {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *nativeContext) SetContext(ctx context.Context) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.ctx = ctx
+	if b, ok := c.b.(*Bot); ok {
+		c.bound = b.withContext(ctx)
+	}
+}
+
+// api returns the API the requests of the context are made with.
+func (c *nativeContext) api() API {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.bound != nil {
+		return c.bound
+	}
+	return c.b
+}
@@ -0,0 +1,87 @@
+package telebot
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// DedupStore remembers the IDs of processed updates, so the same update
+// delivered more than once (webhook retries, failover between instances,
+// restarts) is dropped before reaching the handlers.
+//
+// Use a shared implementation if you run several bot instances.
+type DedupStore interface {
+	// Seen marks the update ID as processed and reports
+	// whether it has been seen before.
+	Seen(id int) (bool, error)
+}
+
+// MemoryDedup is a DedupStore keeping a bounded window
+// of the most recent update IDs in memory.
+type MemoryDedup struct {
+	mu   sync.Mutex
+	ids  map[int]struct{}
+	ring []int
+	next int
+}
+
+// NewMemoryDedup creates a memory store remembering
+// the given number of recent update IDs.
+func NewMemoryDedup(size int) *MemoryDedup {
+	if size < 1 {
+		size = 1
+	}
+	return &MemoryDedup{
+		ids:  make(map[int]struct{}, size),
+		ring: make([]int, 0, size),
+	}
+}
+
+// Seen implements DedupStore.
+func (d *MemoryDedup) Seen(id int) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.ids[id]; ok {
+		return true, nil
+	}
+
+	if len(d.ring) < cap(d.ring) {
+		d.ring = append(d.ring, id)
+	} else {
+		delete(d.ids, d.ring[d.next])
+		d.ring[d.next] = id
+		d.next = (d.next + 1) % len(d.ring)
+	}
+
+	d.ids[id] = struct{}{}
+	return false, nil
+}
+
+// duplicate reports whether the update has already been processed.
+// Updates with zero ID aren't checked, as they don't come from Telegram.
+func (b *Bot) duplicate(u Update) bool {
+	if b.dedup == nil || u.ID == 0 {
+		return false
+	}
+
+	seen, err := b.dedup.Seen(u.ID)
+	if err != nil {
+		// better to process twice than to lose the update
+		b.OnError(err, nil)
+		return false
+	}
+	if seen {
+		atomic.AddInt64(&b.root().duplicates, 1)
+		b.meter().UpdateDropped("duplicate")
+		b.debug(fmt.Errorf("telebot: duplicate update %d is dropped", u.ID))
+	}
+	return seen
+}
+
+// Duplicates returns the number of duplicate updates dropped so far.
+// See Settings.Dedup.
+func (b *Bot) Duplicates() int64 {
+	return atomic.LoadInt64(&b.root().duplicates)
+}
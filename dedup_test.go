@@ -0,0 +1,47 @@
+package telebot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryDedup(t *testing.T) {
+	d := NewMemoryDedup(2)
+
+	seen, _ := d.Seen(1)
+	assert.False(t, seen)
+	seen, _ = d.Seen(1)
+	assert.True(t, seen)
+
+	d.Seen(2)
+	d.Seen(3) // 1 is out of the window
+
+	seen, _ = d.Seen(1)
+	assert.False(t, seen)
+	seen, _ = d.Seen(3)
+	assert.True(t, seen)
+}
+
+func TestBotDedup(t *testing.T) {
+	b, err := NewBot(Settings{
+		Offline:     true,
+		Synchronous: true,
+		Dedup:       NewMemoryDedup(10),
+	})
+	require.NoError(t, err)
+
+	var handled int
+	b.Handle(OnCallback, func(c Context) error {
+		handled++
+		return nil
+	})
+
+	b.ProcessUpdate(Update{ID: 1, Callback: &Callback{}})
+	b.ProcessUpdate(Update{ID: 1, Callback: &Callback{}})
+	b.ProcessUpdate(Update{ID: 2, Callback: &Callback{}})
+
+	assert.Equal(t, 2, handled)
+	assert.Equal(t, int64(1), b.Duplicates())
+}
@@ -0,0 +1,149 @@
+package telebot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+)
+
+// downloadRetries is how many times an interrupted download is resumed.
+var downloadRetries = 3
+
+// fileInfo fetches the file path, rejecting files
+// which exceed the download limit.
+func (b *Bot) fileInfo(file *File) (File, error) {
+	max := b.MaxDownloadSize()
+	if max > 0 && file.FileSize > max {
+		return File{}, ErrFileTooLarge
+	}
+
+	f, err := b.FileByID(file.FileID)
+	if err != nil {
+		return File{}, err
+	}
+
+	file.FilePath = f.FilePath // saving file path
+	if f.FileSize == 0 {
+		f.FileSize = file.FileSize
+	}
+
+	if max > 0 && f.FileSize > max {
+		return File{}, ErrFileTooLarge
+	}
+	return f, nil
+}
+
+// localPath reports whether the file can be read right from the file system.
+func (b *Bot) localPath(f File) bool {
+	return b.local && filepath.IsAbs(f.FilePath)
+}
+
+// fileResponse requests the file content starting from the offset.
+func (b *Bot) fileResponse(f File, offset int64) (*http.Response, error) {
+	url := b.URL + "/file/bot" + b.Token + "/" + f.FilePath
+
+	ctx, cancel := b.withTimeout(b.context(), "", true)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, wrapError(err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		cancel()
+		redactError(err, b.Token)
+		return nil, wrapError(err)
+	}
+	resp.Body = cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	if resp.StatusCode != http.StatusOK && !(offset > 0 && resp.StatusCode == http.StatusPartialContent) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("telebot: expected status 200 but got %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// downloadFrom continues the download from the number of bytes
+// already written to dst.
+func (b *Bot) downloadFrom(f File, dst *downloadWriter, progress ProgressFunc) error {
+	offset := dst.n
+
+	resp, err := b.fileResponse(f, offset)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if offset > 0 && resp.StatusCode == http.StatusOK {
+		// the server ignored the range, skip what's already written
+		if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	if progress != nil {
+		total := f.FileSize
+		if total <= 0 {
+			total = -1
+			if resp.ContentLength >= 0 {
+				total = resp.ContentLength
+				if resp.StatusCode == http.StatusPartialContent {
+					total += offset
+				}
+			}
+		}
+		body = &progressReader{
+			Reader:   body,
+			done:     offset,
+			total:    total,
+			progress: progress,
+		}
+	}
+
+	if _, err := io.Copy(dst, body); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// resumable reports whether the download error is caused
+// by the connection, so the transfer can be resumed.
+func resumable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr)
+}
+
+// checkFileSize verifies the number of downloaded bytes
+// if the size of the file is known.
+func checkFileSize(f File, n int64) error {
+	if f.FileSize > 0 && n != f.FileSize {
+		return fmt.Errorf("%w: got %d of %d bytes", ErrIncompleteFile, n, f.FileSize)
+	}
+	return nil
+}
+
+// downloadWriter counts the written bytes and keeps
+// the error of the underlying writer.
+type downloadWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *downloadWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	if err != nil {
+		w.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,144 @@
+package telebot
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 10<<10)
+
+	var (
+		size     = int64(len(content))
+		getFiles int32
+		requests int32
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getFile"):
+			atomic.AddInt32(&getFiles, 1)
+			fmt.Fprintf(w, `{"ok":true,"result":{"file_id":"1","file_path":"documents/1","file_size":%d}}`, atomic.LoadInt64(&size))
+		case strings.HasSuffix(r.URL.Path, "/documents/1"):
+			var offset int
+			if rng := r.Header.Get("Range"); rng != "" {
+				offset, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(content)-1, len(content)))
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)-offset))
+				w.WriteHeader(http.StatusPartialContent)
+			} else {
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			}
+
+			// drop the connection in the middle of the first transfer
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.Write(content[:len(content)/2])
+				w.(http.Flusher).Flush()
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+			w.Write(content[offset:])
+		}
+	}))
+	defer srv.Close()
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	t.Run("resume", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, b.DownloadTo(&File{FileID: "1"}, &buf))
+		assert.Equal(t, content, buf.Bytes())
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		before := atomic.LoadInt32(&getFiles)
+
+		var buf bytes.Buffer
+		err := b.DownloadTo(&File{FileID: "1", FileSize: CloudMaxDownloadSize + 1}, &buf)
+		assert.ErrorIs(t, err, ErrFileTooLarge)
+		assert.Equal(t, before, atomic.LoadInt32(&getFiles))
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		atomic.StoreInt64(&size, int64(len(content))+1)
+		defer atomic.StoreInt64(&size, int64(len(content)))
+
+		var buf bytes.Buffer
+		err := b.DownloadTo(&File{FileID: "1"}, &buf)
+		assert.ErrorIs(t, err, ErrIncompleteFile)
+
+		dir := t.TempDir()
+		path := filepath.Join(dir, "file")
+		err = b.Download(&File{FileID: "1"}, path)
+		assert.ErrorIs(t, err, ErrIncompleteFile)
+		assert.NoFileExists(t, path)
+
+		// the existing file is kept untouched
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0600))
+		err = b.Download(&File{FileID: "1"}, path)
+		assert.ErrorIs(t, err, ErrIncompleteFile)
+		err = b.Download(&File{FileID: "1", FileSize: CloudMaxDownloadSize + 1}, path)
+		assert.ErrorIs(t, err, ErrFileTooLarge)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "old", string(data))
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(path, []byte("old"), 0600))
+
+		file := &File{FileID: "1"}
+		require.NoError(t, b.Download(file, path))
+		assert.Equal(t, path, file.FileLocal)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+
+		fi, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	})
+
+	t.Run("relative", func(t *testing.T) {
+		dir := t.TempDir()
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(dir))
+		defer os.Chdir(wd)
+
+		// the system temporary directory must not be used
+		tmp := os.Getenv("TMPDIR")
+		os.Setenv("TMPDIR", filepath.Join(dir, "missing"))
+		defer os.Setenv("TMPDIR", tmp)
+
+		file := &File{FileID: "1"}
+		require.NoError(t, b.Download(file, "file.bin"))
+		assert.Equal(t, "file.bin", file.FileLocal)
+
+		data, err := os.ReadFile(filepath.Join(dir, "file.bin"))
+		require.NoError(t, err)
+		assert.Equal(t, content, data)
+	})
+}
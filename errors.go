@@ -3,14 +3,28 @@ package telebot
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strconv"
 	"strings"
 )
 
 type (
+	// Error is an error returned by Bot API. The predefined Err*
+	// variables are of this type as well. The known errors are
+	// returned as their copies carrying the method and the parameters,
+	// so match them with errors.Is rather than ==.
 	Error struct {
 		Code        int
 		Description string
 		Message     string
+
+		// Method is the Bot API method which has returned the error.
+		Method string
+
+		// Parameters are the response parameters
+		// returned along with the error, if any.
+		Parameters map[string]interface{}
 	}
 
 	FloodError struct {
@@ -44,16 +58,79 @@ func (err *Error) Error() string {
 	return fmt.Sprintf("telegram: %s (%d)", msg, err.Code)
 }
 
+// Is reports whether the error matches the target error, so errors.Is
+// works with the predefined errors. The target matches if its description
+// is a prefix of the error description, and the codes are the same or the
+// target code is zero. This way, the unknown errors can be matched too:
+//
+//	errors.Is(err, tele.NewError(400, "Bad Request: message to edit not found"))
+//	errors.Is(err, tele.NewError(0, "Bad Request: wrong file identifier"))
+func (err *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || t.Description == "" {
+		return false
+	}
+	if t.Code != 0 && t.Code != err.Code {
+		return false
+	}
+	return strings.HasPrefix(err.Description, t.Description)
+}
+
+// As fills FloodError and GroupError targets, if the error
+// carries the corresponding response parameters.
+func (err *Error) As(target interface{}) bool {
+	switch t := target.(type) {
+	case *FloodError:
+		if err.RetryAfter() == 0 {
+			return false
+		}
+		*t = FloodError{err: err, RetryAfter: err.RetryAfter()}
+	case *GroupError:
+		if err.MigratedTo() == 0 {
+			return false
+		}
+		*t = GroupError{err: err, MigratedTo: err.MigratedTo()}
+	default:
+		return false
+	}
+	return true
+}
+
+// RetryAfter returns the number of seconds left to wait
+// before the request can be repeated, if it's exceeded
+// the flood control.
+func (err *Error) RetryAfter() int {
+	v, _ := err.Parameters["retry_after"].(float64)
+	return int(v)
+}
+
+// MigratedTo returns the identifier of the supergroup
+// the group has been migrated to.
+func (err *Error) MigratedTo() int64 {
+	v, _ := err.Parameters["migrate_to_chat_id"].(float64)
+	return int64(v)
+}
+
 // Error implements error interface.
 func (err FloodError) Error() string {
 	return err.err.Error()
 }
 
+// Unwrap returns the underlying API error.
+func (err FloodError) Unwrap() error {
+	return err.err
+}
+
 // Error implements error interface.
 func (err GroupError) Error() string {
 	return err.err.Error()
 }
 
+// Unwrap returns the underlying API error.
+func (err GroupError) Unwrap() error {
+	return err.err
+}
+
 // NewError returns new Error instance with given description.
 // First element of msgs is Description. The second is optional Message.
 func NewError(code int, msgs ...string) *Error {
@@ -263,7 +340,108 @@ func ErrIs(s string, err error) bool {
 	return errors.Is(err, Err(s))
 }
 
+// IsRetryable reports whether the request has failed for a temporary
+// reason and may succeed if repeated: the flood control was exceeded,
+// the server has failed, or the connection or a proxy has broken
+// the response.
+func IsRetryable(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+	}
+
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsForbidden reports whether the bot has no access to the chat or user,
+// e.g. it's blocked by the user or kicked from the group.
+func IsForbidden(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == http.StatusForbidden
+}
+
+// IsNotModified reports whether the edit has failed because
+// the new content is the same as the current one.
+func IsNotModified(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == http.StatusBadRequest &&
+		strings.Contains(e.Description, "not modified")
+}
+
+// TransportError is returned when the server responds with something
+// other than a Bot API response, e.g. an HTML error page of a proxy
+// or a truncated body. Such errors are considered retryable.
+type TransportError struct {
+	Method     string
+	StatusCode int
+
+	// Body is the beginning of the response body.
+	Body string
+
+	// Err is the error the body couldn't be decoded with, if any.
+	Err error
+}
+
+// maxErrorBody is the length of the body kept in TransportError.
+const maxErrorBody = 256
+
+func newTransportError(data []byte, err error) *TransportError {
+	body := data
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+	return &TransportError{
+		Body: strings.ToValidUTF8(string(body), ""),
+		Err:  err,
+	}
+}
+
+// Error implements error interface.
+func (err *TransportError) Error() string {
+	msg := fmt.Sprintf("telebot: unexpected response to %s (%d)", err.Method, err.StatusCode)
+	if err.Body != "" {
+		msg += ": " + strconv.Quote(err.Body)
+	}
+	return msg
+}
+
+// Unwrap returns the decoding error.
+func (err *TransportError) Unwrap() error {
+	return err.Err
+}
+
+// checkResponse extracts the error from the Bot API response
+// to the method, taking the status code into account.
+func checkResponse(method string, status int, data []byte) error {
+	err := extractOk(data)
+	switch e := err.(type) {
+	case nil:
+		if status != http.StatusOK {
+			err = &TransportError{Method: method, StatusCode: status}
+		}
+	case *Error:
+		e.Method = method
+	case FloodError:
+		e.err.Method = method
+	case GroupError:
+		e.err.Method = method
+	case *TransportError:
+		e.Method = method
+		e.StatusCode = status
+	}
+	return err
+}
+
 // wrapError returns new wrapped telebot-related error.
+// The bot token is hidden in the URL of the failed request,
+// see redactError.
 func wrapError(err error) error {
+	redactError(err, "")
 	return fmt.Errorf("telebot: %w", err)
 }
@@ -0,0 +1,102 @@
+package telebot
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	err := checkResponse("sendMessage", 403, []byte(`{
+		"ok": false,
+		"error_code": 403,
+		"description": "Forbidden: bot was blocked by the user"
+	}`))
+
+	// the known errors are copies of the predefined ones
+	var apiErr *Error
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "sendMessage", apiErr.Method)
+		assert.NotSame(t, ErrBlockedByUser, apiErr)
+	}
+	assert.Empty(t, ErrBlockedByUser.Method)
+	assert.ErrorIs(t, err, ErrBlockedByUser)
+	assert.ErrorIs(t, fmt.Errorf("wrapped: %w", err), ErrBlockedByUser)
+	assert.NotErrorIs(t, err, ErrKickedFromGroup)
+	assert.True(t, IsForbidden(err))
+	assert.False(t, IsRetryable(err))
+
+	err = checkResponse("sendMessage", 403, []byte(`{
+		"ok": false,
+		"error_code": 403,
+		"description": "Forbidden: bot can't send messages to bots"
+	}`))
+
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "sendMessage", apiErr.Method)
+		assert.Equal(t, 403, apiErr.Code)
+	}
+	assert.True(t, IsForbidden(err))
+
+	// unknown errors are matched by the description prefix
+	err = extractOk([]byte(`{
+		"ok": false,
+		"error_code": 400,
+		"description": "Bad Request: message to edit not found"
+	}`))
+	assert.EqualError(t, err, "telegram: Bad Request: message to edit not found (400)")
+	assert.ErrorIs(t, err, NewError(400, "Bad Request: message to edit"))
+	assert.ErrorIs(t, err, NewError(0, "Bad Request: message to edit"))
+	assert.NotErrorIs(t, err, NewError(403, "Bad Request: message to edit"))
+	assert.NotErrorIs(t, err, NewError(400, ""))
+
+	err = extractOk([]byte(`{
+		"ok": false,
+		"error_code": 400,
+		"description": "` + ErrSameMessageContent.Description + `"
+	}`))
+	assert.ErrorIs(t, err, ErrSameMessageContent)
+	assert.ErrorIs(t, err, ErrMessageNotModified)
+	assert.True(t, IsNotModified(err))
+
+	assert.True(t, IsRetryable(ErrInternal))
+	assert.True(t, IsRetryable(wrapError(&net.OpError{Op: "dial", Err: errors.New("refused")})))
+	assert.False(t, IsRetryable(errors.New("telebot: other")))
+}
+
+func TestTransportError(t *testing.T) {
+	err := checkResponse("sendMessage", 502, []byte("<html>502 Bad Gateway</html>"))
+
+	var transportErr *TransportError
+	if assert.True(t, errors.As(err, &transportErr)) {
+		assert.Equal(t, "sendMessage", transportErr.Method)
+		assert.Equal(t, 502, transportErr.StatusCode)
+		assert.Equal(t, "<html>502 Bad Gateway</html>", transportErr.Body)
+	}
+	assert.EqualError(t, err, `telebot: unexpected response to sendMessage (502): "<html>502 Bad Gateway</html>"`)
+	assert.True(t, IsRetryable(err))
+
+	// truncated body
+	err = checkResponse("sendMessage", 200, []byte(`{"ok":true,"res`))
+	assert.True(t, errors.As(err, &transportErr))
+	assert.Error(t, transportErr.Unwrap())
+
+	// JSON of a proxy
+	err = checkResponse("sendMessage", 503, []byte(`{"message":"unavailable"}`))
+	assert.True(t, errors.As(err, &transportErr))
+
+	// successful body with an error status
+	err = checkResponse("sendMessage", 502, []byte(`{"ok":true,"result":true}`))
+	assert.True(t, errors.As(err, &transportErr))
+
+	long := strings.Repeat("x", 1000)
+	err = checkResponse("getMe", 200, []byte(long))
+	assert.True(t, errors.As(err, &transportErr))
+	assert.Len(t, transportErr.Body, maxErrorBody)
+
+	assert.NoError(t, checkResponse("getMe", 200, []byte(`{"ok":true,"result":{}}`)))
+}
@@ -1,8 +1,14 @@
 package telebot
 
 import (
+	"bytes"
 	"io"
+	"io/fs"
+	"io/ioutil"
+	"mime"
 	"os"
+	"path"
+	"path/filepath"
 )
 
 // File object represents any sort of file.
@@ -24,8 +30,21 @@ type File struct {
 	FileReader io.Reader `json:"-"`
 
 	fileName string
+	mimeType string
+	hooks    *fileHooks
 }
 
+// fileHooks holds the funcs of the file. They are kept out of File,
+// so it stays comparable.
+type fileHooks struct {
+	open     FileOpener
+	progress ProgressFunc
+}
+
+// FileOpener opens the content of the file. It's called on every
+// upload attempt, so each call must return a new stream.
+type FileOpener func() (io.ReadCloser, error)
+
 // FromDisk constructs a new local (on-disk) file object.
 //
 // Note, it returns File, not *File for a very good reason:
@@ -65,6 +84,125 @@ func FromReader(reader io.Reader) File {
 	return File{FileReader: reader}
 }
 
+// FromBytes constructs a new file from the in-memory content.
+// Unlike FromReader, the file can be sent any number of times.
+//
+//	doc := &tele.Document{File: tele.FromBytes("report.csv", data)}
+func FromBytes(name string, data []byte) File {
+	f := FromOpener(name, func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	})
+	f.FileSize = int64(len(data))
+	return f
+}
+
+// FromFS constructs a new file from the file system, e.g. embed.FS.
+//
+//	//go:embed assets
+//	var assets embed.FS
+//
+//	photo := &tele.Photo{File: tele.FromFS(assets, "assets/logo.png")}
+func FromFS(fsys fs.FS, name string) File {
+	f := FromOpener(path.Base(name), func() (io.ReadCloser, error) {
+		return fsys.Open(name)
+	})
+	if fi, err := fs.Stat(fsys, name); err == nil {
+		f.FileSize = fi.Size()
+	}
+	return f
+}
+
+// FromOpener constructs a new file which content is opened by the
+// given function. It's called on every upload, so the file can be
+// sent any number of times.
+//
+//	doc := &tele.Document{File: tele.FromOpener("dump.sql", func() (io.ReadCloser, error) {
+//		return storage.Open("dump.sql")
+//	})}
+func FromOpener(name string, open FileOpener) File {
+	return File{fileName: name, hooks: &fileHooks{open: open}}
+}
+
+// WithName returns a copy of the file with the name
+// used for the upload.
+func (f File) WithName(name string) File {
+	f.fileName = name
+	return f
+}
+
+// WithMIME returns a copy of the file with the MIME type
+// used for the upload. By default, it's detected by the extension.
+func (f File) WithMIME(mimeType string) File {
+	f.mimeType = mimeType
+	return f
+}
+
+// WithProgress returns a copy of the file, which calls the given func
+// as the file is being uploaded or downloaded.
+//
+//	doc := &tele.Document{File: tele.FromDisk("report.pdf").WithProgress(onProgress)}
+func (f File) WithProgress(progress ProgressFunc) File {
+	var hooks fileHooks
+	if f.hooks != nil {
+		hooks = *f.hooks
+	}
+	hooks.progress = progress
+	f.hooks = &hooks
+	return f
+}
+
+func (f *File) opener() FileOpener {
+	if f.hooks == nil {
+		return nil
+	}
+	return f.hooks.open
+}
+
+func (f *File) progress() ProgressFunc {
+	if f.hooks == nil {
+		return nil
+	}
+	return f.hooks.progress
+}
+
+func (f *File) setFileMeta(name, mimeType string) {
+	if name != "" {
+		f.fileName = name
+	}
+	if mimeType != "" {
+		f.mimeType = mimeType
+	}
+}
+
+// uploadName returns the name of the file used for the upload.
+func (f *File) uploadName() string {
+	if f.fileName == "" && f.FileLocal != "" {
+		return filepath.Base(f.FileLocal)
+	}
+	return f.fileName
+}
+
+// uploadMIME returns the MIME type of the file used for the upload.
+func (f *File) uploadMIME() string {
+	if f.mimeType != "" {
+		return f.mimeType
+	}
+	if t := mime.TypeByExtension(filepath.Ext(f.uploadName())); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// readable tells whether the file content can be uploaded.
+func (f *File) readable() bool {
+	return f.OnDisk() || f.opener() != nil || f.FileReader != nil
+}
+
+// replayable tells whether the file content can be read more than once.
+func (f *File) replayable() bool {
+	return f.OnDisk() || f.opener() != nil
+}
+
 func (f *File) stealRef(g *File) {
 	if g.OnDisk() {
 		f.FileLocal = g.FileLocal
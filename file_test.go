@@ -1,10 +1,20 @@
 package telebot
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFile(t *testing.T) {
@@ -22,3 +32,119 @@ func TestFile(t *testing.T) {
 	assert.Equal(t, g.FileLocal, f.FileLocal)
 	assert.Equal(t, f.FileURL, g.FileURL)
 }
+
+func TestFileSources(t *testing.T) {
+	type part struct {
+		name, mime, data string
+	}
+
+	var (
+		mu       sync.Mutex
+		parts    []part
+		failures int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		mr, err := r.MultipartReader()
+		require.NoError(t, err)
+
+		var p part
+		for {
+			fp, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			if fp.FileName() != "" {
+				data, _ := io.ReadAll(fp)
+				p = part{fp.FileName(), fp.Header.Get("Content-Type"), string(data)}
+			}
+		}
+
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		parts = append(parts, p)
+		w.Write([]byte(`{"ok":true,"result":{"document":{"file_id":"1"}}}`))
+	}))
+	defer srv.Close()
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	fsys := fstest.MapFS{"assets/logo.png": {Data: []byte("png")}}
+	chat := &Chat{ID: 1}
+
+	file := FromBytes("report.pdf", []byte("pdf"))
+	for i := 0; i < 2; i++ {
+		_, err = b.Send(chat, &Document{File: file})
+		require.NoError(t, err)
+	}
+
+	_, err = b.Send(chat, &Document{File: FromFS(fsys, "assets/logo.png")})
+	require.NoError(t, err)
+
+	_, err = b.Send(chat, &Document{File: FromBytes("data", []byte("{}")).WithMIME("application/json")})
+	require.NoError(t, err)
+
+	_, err = b.Send(chat, &Document{File: FromBytes("data", []byte("x")), FileName: "x.html"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []part{
+		{"report.pdf", "application/pdf", "pdf"},
+		{"report.pdf", "application/pdf", "pdf"},
+		{"logo.png", "image/png", "png"},
+		{"data", "application/json", "{}"},
+		{"x.html", "text/html; charset=utf-8", "x"},
+	}, parts)
+
+	// the request may have been handled, so it's never repeated
+	failures = 1
+	sent := len(parts)
+	_, err = b.Send(chat, &Document{File: FromBytes("retry.txt", []byte("retry"))})
+	assert.ErrorIs(t, err, ErrInternal)
+	assert.Len(t, parts, sent)
+
+	// replayable files are uploaded again if the connection fails
+	var dials int
+	dialer := &net.Dialer{}
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			mu.Lock()
+			dials++
+			refused := dials == 1
+			mu.Unlock()
+			if refused {
+				return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+			}
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}}
+
+	b, err = NewBot(Settings{URL: srv.URL, Offline: true, Client: client, UploadRetries: 1})
+	require.NoError(t, err)
+
+	var opened int
+	opener := FromOpener("opened.txt", func() (io.ReadCloser, error) {
+		opened++
+		return io.NopCloser(bytes.NewReader([]byte("opened"))), nil
+	})
+	_, err = b.Send(chat, &Document{File: opener})
+	require.NoError(t, err)
+	assert.Equal(t, 2, opened)
+	assert.Equal(t, "opened", parts[len(parts)-1].data)
+
+	// the content of a reader can't be read twice
+	client.CloseIdleConnections()
+	mu.Lock()
+	dials = 0
+	mu.Unlock()
+
+	_, err = b.Send(chat, &Document{File: FromReader(strings.NewReader("once"))})
+	assert.True(t, unsent(err))
+}
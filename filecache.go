@@ -0,0 +1,253 @@
+package telebot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileCache remembers the IDs Telegram assigns to uploaded files, so
+// the same content sent again is passed by its ID instead of being
+// uploaded once more. Files on disk and the ones with FileOpener are
+// keyed by the hash of their content, files sent by URL by the URL
+// itself. Files with a one-shot FileReader aren't cached.
+//
+// Use a shared implementation if you run several bot instances.
+type FileCache interface {
+	// Get returns the file ID stored under the key,
+	// or an empty string if there is none.
+	Get(key string) (string, error)
+
+	// Set stores the file ID under the key.
+	Set(key, fileID string) error
+
+	// Delete removes the file ID stored under the key, if any.
+	// It's called once Telegram rejects the ID.
+	Delete(key string) error
+}
+
+// MemoryFileCache is a FileCache kept in memory.
+type MemoryFileCache struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewMemoryFileCache creates an empty memory cache.
+func NewMemoryFileCache() *MemoryFileCache {
+	return &MemoryFileCache{ids: make(map[string]string)}
+}
+
+// Get implements FileCache.
+func (c *MemoryFileCache) Get(key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ids[key], nil
+}
+
+// Set implements FileCache.
+func (c *MemoryFileCache) Set(key, fileID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids[key] = fileID
+	return nil
+}
+
+// Delete implements FileCache.
+func (c *MemoryFileCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.ids, key)
+	return nil
+}
+
+// DiskFileCache is a FileCache persisted as a JSON file,
+// so the IDs survive restarts of the bot.
+type DiskFileCache struct {
+	path string
+
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// NewDiskFileCache loads the cache from the given file,
+// which is created on the first Set if it doesn't exist.
+func NewDiskFileCache(path string) (*DiskFileCache, error) {
+	c := &DiskFileCache{
+		path: path,
+		ids:  make(map[string]string),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &c.ids); err != nil {
+			return nil, wrapError(err)
+		}
+	}
+	return c, nil
+}
+
+// Get implements FileCache.
+func (c *DiskFileCache) Get(key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ids[key], nil
+}
+
+// Set implements FileCache. The whole cache is rewritten.
+func (c *DiskFileCache) Set(key, fileID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ids[key] == fileID {
+		return nil
+	}
+	c.ids[key] = fileID
+	return c.save()
+}
+
+// Delete implements FileCache. The whole cache is rewritten.
+func (c *DiskFileCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.ids[key]; !ok {
+		return nil
+	}
+	delete(c.ids, key)
+	return c.save()
+}
+
+// save writes the cache to the file, c.mu must be held.
+func (c *DiskFileCache) save() error {
+	data, err := json.Marshal(c.ids)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	// write and rename, so the cache is never left half-written
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return wrapError(err)
+	}
+	if err := ioutil.WriteFile(c.path+".tmp", data, 0600); err != nil {
+		return wrapError(err)
+	}
+	if err := os.Rename(c.path+".tmp", c.path); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// fileCacheKey builds the cache key of the file sent as the given
+// kind of media. File IDs are only valid for the same kind, so it's
+// a part of the key. Returns an empty key if the file can't be cached.
+func fileCacheKey(kind string, f *File) (string, error) {
+	switch {
+	case f.InCloud():
+		return "", nil
+	case f.FileURL != "":
+		return kind + ":url:" + f.FileURL, nil
+	case f.OnDisk():
+		return hashFile(kind, func() (io.ReadCloser, error) {
+			return os.Open(f.FileLocal)
+		})
+	case f.opener() != nil:
+		return hashFile(kind, f.opener())
+	}
+	return "", nil
+}
+
+func hashFile(kind string, open FileOpener) (string, error) {
+	r, err := open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return kind + ":sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cachedFile substitutes the file with its cached ID, if any. It returns
+// the key the uploaded file should be recorded under and reports whether
+// the ID was substituted.
+func (b *Bot) cachedFile(kind string, f *File) (key string, hit bool) {
+	if b.fileCache == nil {
+		return "", false
+	}
+
+	key, err := fileCacheKey(kind, f)
+	if err != nil || key == "" {
+		// let the upload itself fail with a proper error
+		return "", false
+	}
+
+	id, err := b.fileCache.Get(key)
+	if err != nil {
+		b.OnError(err, nil)
+		return key, false
+	}
+	if id == "" {
+		return key, false
+	}
+
+	*f = File{FileID: id}
+	return key, true
+}
+
+// cacheFile records the ID of the sent media under the key,
+// unless Telegram has changed the kind of the media.
+func (b *Bot) cacheFile(key, kind string, m Media) {
+	if key == "" || m == nil || m.MediaType() != kind {
+		return
+	}
+	if id := m.MediaFile().FileID; id != "" {
+		if err := b.fileCache.Set(key, id); err != nil {
+			b.OnError(err, nil)
+		}
+	}
+}
+
+// evictFile removes the file ID rejected by Telegram from the cache.
+func (b *Bot) evictFile(key string) {
+	if err := b.fileCache.Delete(key); err != nil {
+		b.OnError(err, nil)
+	}
+}
+
+// staleFileID reports whether the request sent with a cached file ID
+// failed as Telegram rejected the ID, so the file is worth uploading.
+// Other errors would fail the upload as well, so it isn't retried.
+func staleFileID(err error) bool {
+	for _, stale := range []error{
+		ErrWrongFileID,
+		ErrWrongFileIDCharacter,
+		ErrWrongFileIDLength,
+		ErrWrongFileIDPadding,
+		ErrWrongFileIDSymbol,
+	} {
+		if errors.Is(err, stale) {
+			return true
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "wrong file identifier") ||
+		strings.Contains(msg, "wrong remote file id")
+}
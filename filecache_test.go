@@ -0,0 +1,156 @@
+package telebot
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCache(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		uploads int
+		sent    []string
+		stale   bool
+		blocked bool
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var id string
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			uploads++
+			id = "uploaded"
+		} else {
+			var params map[string]string
+			json.NewDecoder(r.Body).Decode(&params)
+			id = params["document"]
+			if id == "" {
+				id = params["media"]
+			}
+		}
+		sent = append(sent, id)
+
+		if blocked {
+			w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+			return
+		}
+		if stale && strings.Contains(id, "uploaded") && !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: wrong file identifier/HTTP URL specified"}`))
+			return
+		}
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendDocument"):
+			w.Write([]byte(`{"ok":true,"result":{"document":{"file_id":"uploaded"}}}`))
+		case strings.HasSuffix(r.URL.Path, "/sendMediaGroup"):
+			w.Write([]byte(`{"ok":true,"result":[{"photo":[{"file_id":"uploaded"}]},{"photo":[{"file_id":"uploaded"}]}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	require.NoError(t, ioutil.WriteFile(path, []byte("report"), 0600))
+
+	cache := NewMemoryFileCache()
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, FileCache: cache})
+	require.NoError(t, err)
+
+	chat := &Chat{ID: 1}
+	send := func() {
+		_, err := b.Send(chat, &Document{File: FromDisk(path)})
+		require.NoError(t, err)
+	}
+
+	send()
+	send()
+	assert.Equal(t, 1, uploads)
+	assert.Equal(t, []string{"uploaded", "uploaded"}, sent)
+
+	// the same content as a photo is uploaded separately
+	_, err = b.SendAlbum(chat, Album{
+		&Photo{File: FromDisk(path)},
+		&Photo{File: FromDisk(path)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, uploads)
+
+	_, err = b.SendAlbum(chat, Album{&Photo{File: FromDisk(path)}, &Photo{File: FromDisk(path)}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, uploads)
+
+	// other errors are not worth the upload
+	blocked = true
+	_, err = b.Send(chat, &Document{File: FromDisk(path)})
+	assert.ErrorIs(t, err, ErrBlockedByUser)
+	assert.Equal(t, 2, uploads)
+	blocked = false
+
+	key, err := fileCacheKey("document", &File{FileLocal: path})
+	require.NoError(t, err)
+	id, _ := cache.Get(key)
+	assert.Equal(t, "uploaded", id)
+
+	// stale IDs are evicted and replaced by a new upload
+	stale = true
+	send()
+	assert.Equal(t, 3, uploads)
+
+	// the album is uploaded again once any of its IDs is rejected
+	_, err = b.SendAlbum(chat, Album{&Photo{File: FromDisk(path)}, &Photo{File: FromDisk(path)}})
+	require.NoError(t, err)
+	assert.Equal(t, 4, uploads)
+}
+
+func TestStaleFileID(t *testing.T) {
+	assert.True(t, staleFileID(ErrWrongFileID))
+	assert.True(t, staleFileID(ErrWrongFileIDLength))
+	assert.True(t, staleFileID(&Error{Code: 400, Description: "Bad Request: wrong remote file identifier specified: can't unserialize it"}))
+	assert.False(t, staleFileID(ErrBlockedByUser))
+	assert.False(t, staleFileID(&Error{Code: 429, Description: "Too Many Requests: retry after 1"}))
+	assert.False(t, staleFileID(errors.New("dial tcp: connection refused")))
+}
+
+func TestDiskFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "files.json")
+
+	c, err := NewDiskFileCache(path)
+	require.NoError(t, err)
+	require.NoError(t, c.Set("photo:url:https://example.com/a.jpg", "1"))
+	assert.FileExists(t, path)
+
+	c, err = NewDiskFileCache(path)
+	require.NoError(t, err)
+
+	id, err := c.Get("photo:url:https://example.com/a.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, "1", id)
+
+	require.NoError(t, c.Delete("photo:url:https://example.com/a.jpg"))
+	c, err = NewDiskFileCache(path)
+	require.NoError(t, err)
+
+	id, err = c.Get("photo:url:https://example.com/a.jpg")
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	id, err = c.Get("photo:url:https://example.com/b.jpg")
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	_, err = NewDiskFileCache(path)
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package layout
 
 import (
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	tele "github.com/irijopa/telebot"
@@ -11,23 +12,34 @@ import (
 // Config represents typed map interface related to the "config" section in layout.
 type Config struct {
 	v *viper.Viper
+
+	// live holds the *layoutData of the layout the config belongs to,
+	// so the root config follows the reloads.
+	live *atomic.Value
+}
+
+func (c *Config) viper() *viper.Viper {
+	if c.live != nil {
+		return c.live.Load().(*layoutData).config
+	}
+	return c.v
 }
 
 // Unmarshal parses the whole config into the out value. It's useful when you want to
 // describe and to pre-define the fields in your custom configuration struct.
 func (c *Config) Unmarshal(v interface{}) error {
-	return c.v.Unmarshal(v)
+	return c.viper().Unmarshal(v)
 }
 
 // UnmarshalKey parses the specific key in the config into the out value.
 func (c *Config) UnmarshalKey(k string, v interface{}) error {
-	return c.v.UnmarshalKey(k, v)
+	return c.viper().UnmarshalKey(k, v)
 }
 
 // Get returns a child map field wrapped into Config.
 // If the field isn't a map, returns nil.
 func (c *Config) Get(k string) *Config {
-	v := c.v.Sub(k)
+	v := c.viper().Sub(k)
 	if v == nil {
 		return nil
 	}
@@ -37,7 +49,7 @@ func (c *Config) Get(k string) *Config {
 // Slice returns a child slice of objects wrapped into Config.
 // If the field isn't a slice, returns nil.
 func (c *Config) Slice(k string) (slice []*Config) {
-	a, ok := c.v.Get(k).([]interface{})
+	a, ok := c.viper().Get(k).([]interface{})
 	if !ok {
 		return nil
 	}
@@ -58,33 +70,33 @@ func (c *Config) Slice(k string) (slice []*Config) {
 
 // String returns a field casted to the string.
 func (c *Config) String(k string) string {
-	return c.v.GetString(k)
+	return c.viper().GetString(k)
 }
 
 // Int returns a field casted to the int.
 func (c *Config) Int(k string) int {
-	return c.v.GetInt(k)
+	return c.viper().GetInt(k)
 }
 
 // Int64 returns a field casted to the int64.
 func (c *Config) Int64(k string) int64 {
-	return c.v.GetInt64(k)
+	return c.viper().GetInt64(k)
 }
 
 // Float returns a field casted to the float64.
 func (c *Config) Float(k string) float64 {
-	return c.v.GetFloat64(k)
+	return c.viper().GetFloat64(k)
 }
 
 // Bool returns a field casted to the bool.
 func (c *Config) Bool(k string) bool {
-	return c.v.GetBool(k)
+	return c.viper().GetBool(k)
 }
 
 // Duration returns a field casted to the time.Duration.
 // Accepts number-represented duration or a string in 0nsuµmh format.
 func (c *Config) Duration(k string) time.Duration {
-	return c.v.GetDuration(k)
+	return c.viper().GetDuration(k)
 }
 
 // ChatID returns a field casted to the ChatID.
@@ -95,12 +107,12 @@ func (c *Config) ChatID(k string) tele.ChatID {
 
 // Strings returns a field casted to the string slice.
 func (c *Config) Strings(k string) []string {
-	return c.v.GetStringSlice(k)
+	return c.viper().GetStringSlice(k)
 }
 
 // Ints returns a field casted to the int slice.
 func (c *Config) Ints(k string) []int {
-	return c.v.GetIntSlice(k)
+	return c.viper().GetIntSlice(k)
 }
 
 // Int64s returns a field casted to the int64 slice.
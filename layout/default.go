@@ -22,6 +22,11 @@ func (dlt *DefaultLayout) Text(k string, args ...interface{}) string {
 	return dlt.lt.TextLocale(dlt.locale, k, args...)
 }
 
+// Plural wraps localized layout function Plural using your default locale.
+func (dlt *DefaultLayout) Plural(k string, count interface{}, args ...interface{}) string {
+	return dlt.lt.PluralLocale(dlt.locale, k, count, args...)
+}
+
 // Callback returns a callback endpoint used to handle buttons.
 func (dlt *DefaultLayout) Callback(k string) tele.CallbackEndpoint {
 	return dlt.lt.Callback(k)
@@ -4,34 +4,48 @@ import (
 	"bytes"
 	"encoding/json"
 	"io/fs"
-	"log"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 
 	"github.com/goccy/go-yaml"
 	tele "github.com/irijopa/telebot"
+	"github.com/spf13/viper"
 )
 
 type (
 	// Layout provides an interface to interact with the layout,
 	// parsed from the config file and locales.
 	Layout struct {
-		pref  *tele.Settings
-		mu    sync.RWMutex // protects ctxs
-		ctxs  map[tele.Context]string
-		funcs template.FuncMap
+		logger  tele.Logger
+		mu      sync.RWMutex // protects ctxs, rules and missing
+		ctxs    map[tele.Context]string
+		rules   map[string]PluralRule
+		missing Missing
+		funcs   template.FuncMap
 
-		commands map[string]string
-		buttons  map[string]Button
-		markups  map[string]Markup
-		results  map[string]Result
-		locales  map[string]*template.Template
+		fsys fs.FS // nil for the OS filesystem
+		path string
+		data atomic.Value // *layoutData
 
 		Config
 	}
 
+	// layoutData is the parsed content of the layout,
+	// replaced as a whole on reload.
+	layoutData struct {
+		pref       *tele.Settings
+		config     *viper.Viper
+		commands   map[string]string
+		buttons    map[string]Button
+		markups    map[string]Markup
+		results    map[string]Result
+		locales    map[string]*template.Template
+		localesDir string
+	}
+
 	// Button is a shortcut for tele.Btn.
 	Button struct {
 		tele.Btn `yaml:",inline"`
@@ -70,28 +84,24 @@ type (
 
 // New parses the given layout file.
 func New(path string, funcs ...template.FuncMap) (*Layout, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-	return rawNew(data, funcs...)
+	return rawNew(nil, path, funcs...)
 }
 
 // NewFromFS parses the layout from the given fs.FS. It allows to read layout
 // from the go:embed filesystem.
 func NewFromFS(fsys fs.FS, path string, funcs ...template.FuncMap) (*Layout, error) {
-	data, err := fs.ReadFile(fsys, path)
-	if err != nil {
-		return nil, err
-	}
-	return rawNew(data, funcs...)
+	return rawNew(fsys, path, funcs...)
 }
 
-func rawNew(data []byte, funcs ...template.FuncMap) (*Layout, error) {
-	lt := Layout{
+func rawNew(fsys fs.FS, path string, funcs ...template.FuncMap) (*Layout, error) {
+	lt := &Layout{
 		ctxs:  make(map[tele.Context]string),
 		funcs: make(template.FuncMap),
+		fsys:  fsys,
+		path:  path,
 	}
+	lt.Config = Config{live: &lt.data}
+	lt.data.Store(&layoutData{config: viper.New()})
 
 	for k, v := range builtinFuncs {
 		lt.funcs[k] = v
@@ -102,7 +112,29 @@ func rawNew(data []byte, funcs ...template.FuncMap) (*Layout, error) {
 		}
 	}
 
-	return &lt, yaml.Unmarshal(data, &lt)
+	data, err := lt.read()
+	if err != nil {
+		return nil, err
+	}
+	return lt, yaml.Unmarshal(data, lt)
+}
+
+// read reads the layout file.
+func (lt *Layout) read() ([]byte, error) {
+	if lt.fsys != nil {
+		return fs.ReadFile(lt.fsys, lt.path)
+	}
+	return os.ReadFile(lt.path)
+}
+
+// load returns the current version of the layout.
+func (lt *Layout) load() *layoutData {
+	return lt.data.Load().(*layoutData)
+}
+
+// store replaces the current version of the layout.
+func (lt *Layout) store(d *layoutData) {
+	lt.data.Store(d)
 }
 
 // NewDefault parses the given layout file without localization features.
@@ -120,12 +152,14 @@ var builtinFuncs = template.FuncMap{
 	"locale": func() string { return "" },
 	"config": func(string) string { return "" },
 	"text":   func(string, ...interface{}) string { return "" },
+	"plural": func(string, interface{}, ...interface{}) string { return "" },
 }
 
 // Settings returns built telebot Settings required for bot initializing.
 //
 //	settings:
 //		url: (custom url if needed)
+//		local: (true if url is a local Bot API server)
 //		token: (not recommended)
 //		updates: (chan capacity)
 //		locales_dir: (optional)
@@ -133,6 +167,7 @@ var builtinFuncs = template.FuncMap{
 //		parse_mode: (default parse mode)
 //		long_poller: (long poller settings)
 //		webhook: (or webhook settings)
+//		transport: (proxy, ca_file and timeouts)
 //
 // Usage:
 //
@@ -140,12 +175,32 @@ var builtinFuncs = template.FuncMap{
 //	b, err := tele.NewBot(lt.Settings())
 //	// That's all!
 func (lt *Layout) Settings() tele.Settings {
-	if lt.pref == nil {
+	pref := lt.load().pref
+	if pref == nil {
 		panic("telebot/layout: settings is empty")
 	}
-	return *lt.pref
+	return *pref
+}
+
+// SetLogger sets the logger receiving the errors of the layout,
+// e.g. the failed template executions. By default, the standard
+// library logger is used. Usually, it's the logger of the bot:
+//
+//	lt.SetLogger(b.Logger())
+func (lt *Layout) SetLogger(logger tele.Logger) {
+	lt.logger = logger
+}
+
+func (lt *Layout) logError(msg string, fields ...interface{}) {
+	logger := lt.logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+	logger.Log(tele.LevelError, "telebot/layout: "+msg, fields...)
 }
 
+var defaultLogger = tele.NewStdLogger(nil, tele.LevelDebug)
+
 // Default returns a simplified layout instance with the pre-defined locale.
 // It's useful when you have no need for localization and don't want to pass
 // context each time you use layout functions.
@@ -160,7 +215,7 @@ func (lt *Layout) Default(locale string) *DefaultLayout {
 // Locales returns all presented locales.
 func (lt *Layout) Locales() []string {
 	var keys []string
-	for k := range lt.locales {
+	for k := range lt.load().locales {
 		keys = append(keys, k)
 	}
 	return keys
@@ -184,7 +239,7 @@ func (lt *Layout) SetLocale(c tele.Context, locale string) {
 // Commands returns a list of telebot commands, which can be
 // used in b.SetCommands later.
 func (lt *Layout) Commands() (cmds []tele.Command) {
-	for k, v := range lt.commands {
+	for k, v := range lt.load().commands {
 		cmds = append(cmds, tele.Command{
 			Text:        strings.TrimLeft(k, "/"),
 			Description: v,
@@ -219,16 +274,16 @@ func (lt *Layout) CommandsLocale(locale string, args ...interface{}) (cmds []tel
 		arg = args[0]
 	}
 
-	for k, v := range lt.commands {
+	for k, v := range lt.load().commands {
 		tmpl, err := lt.template(template.New(k).Funcs(lt.funcs), locale).Parse(v)
 		if err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("parse command template", "locale", locale, "command", k, "error", err)
 			return nil
 		}
 
 		var buf bytes.Buffer
 		if err := tmpl.Execute(&buf, arg); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("execute command template", "locale", locale, "command", k, "error", err)
 			return nil
 		}
 
@@ -262,11 +317,17 @@ func (lt *Layout) Text(c tele.Context, k string, args ...interface{}) string {
 }
 
 // TextLocale returns a localized text processed with text/template engine.
+// The text missing in the regional locale, e.g. pt-BR, is taken from
+// its language, e.g. pt, and then resolved as set by SetMissing.
 // See Text for more details.
 func (lt *Layout) TextLocale(locale, k string, args ...interface{}) string {
-	tmpl, ok := lt.locales[locale]
+	tmpl, ok := lt.find(locale, func(tmpl *template.Template) bool {
+		return tmpl.Lookup(k) != nil
+	})
 	if !ok {
-		return ""
+		return lt.resolveMissing(locale, k, func(fallback string) string {
+			return lt.TextLocale(fallback, k, args...)
+		})
 	}
 
 	var arg interface{}
@@ -276,7 +337,7 @@ func (lt *Layout) TextLocale(locale, k string, args ...interface{}) string {
 
 	var buf bytes.Buffer
 	if err := lt.template(tmpl, locale).ExecuteTemplate(&buf, k, arg); err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("execute text template", "locale", locale, "key", k, "error", err)
 	}
 
 	return buf.String()
@@ -289,7 +350,7 @@ func (lt *Layout) TextLocale(locale, k string, args ...interface{}) string {
 //	// Handling settings button
 //	b.Handle(lt.Callback("settings"), onSettings)
 func (lt *Layout) Callback(k string) tele.CallbackEndpoint {
-	btn, ok := lt.buttons[k]
+	btn, ok := lt.load().buttons[k]
 	if !ok {
 		return nil
 	}
@@ -333,7 +394,7 @@ func (lt *Layout) Button(c tele.Context, k string, args ...interface{}) *tele.Bt
 // ButtonLocale returns a localized button processed with text/template engine.
 // See Button for more details.
 func (lt *Layout) ButtonLocale(locale, k string, args ...interface{}) *tele.Btn {
-	btn, ok := lt.buttons[k]
+	btn, ok := lt.load().buttons[k]
 	if !ok {
 		return nil
 	}
@@ -345,24 +406,24 @@ func (lt *Layout) ButtonLocale(locale, k string, args ...interface{}) *tele.Btn
 
 	data, err := yaml.Marshal(btn)
 	if err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("marshal button", "button", k, "error", err)
 		return nil
 	}
 
 	tmpl, err := lt.template(template.New(k).Funcs(lt.funcs), locale).Parse(string(data))
 	if err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("parse button template", "locale", locale, "button", k, "error", err)
 		return nil
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, arg); err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("execute button template", "locale", locale, "button", k, "error", err)
 		return nil
 	}
 
 	if err := yaml.Unmarshal(buf.Bytes(), &btn); err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("unmarshal button", "locale", locale, "button", k, "error", err)
 		return nil
 	}
 
@@ -398,7 +459,7 @@ func (lt *Layout) Markup(c tele.Context, k string, args ...interface{}) *tele.Re
 // MarkupLocale returns a localized markup processed with text/template engine.
 // See Markup for more details.
 func (lt *Layout) MarkupLocale(locale, k string, args ...interface{}) *tele.ReplyMarkup {
-	markup, ok := lt.markups[k]
+	markup, ok := lt.load().markups[k]
 	if !ok {
 		return nil
 	}
@@ -410,13 +471,13 @@ func (lt *Layout) MarkupLocale(locale, k string, args ...interface{}) *tele.Repl
 
 	var buf bytes.Buffer
 	if err := lt.template(markup.keyboard, locale).Execute(&buf, arg); err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("execute markup template", "locale", locale, "markup", k, "error", err)
 	}
 
 	r := &tele.ReplyMarkup{}
 	if *markup.inline {
 		if err := yaml.Unmarshal(buf.Bytes(), &r.InlineKeyboard); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal inline keyboard", "locale", locale, "markup", k, "error", err)
 		}
 	} else {
 		r.ResizeKeyboard = markup.ResizeKeyboard == nil || *markup.ResizeKeyboard
@@ -426,7 +487,7 @@ func (lt *Layout) MarkupLocale(locale, k string, args ...interface{}) *tele.Repl
 		r.Selective = markup.Selective
 
 		if err := yaml.Unmarshal(buf.Bytes(), &r.ReplyKeyboard); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal reply keyboard", "locale", locale, "markup", k, "error", err)
 		}
 	}
 
@@ -469,7 +530,7 @@ func (lt *Layout) Result(c tele.Context, k string, args ...interface{}) tele.Res
 // ResultLocale returns a localized result processed with text/template engine.
 // See Result for more details.
 func (lt *Layout) ResultLocale(locale, k string, args ...interface{}) tele.Result {
-	result, ok := lt.results[k]
+	result, ok := lt.load().results[k]
 	if !ok {
 		return nil
 	}
@@ -481,7 +542,7 @@ func (lt *Layout) ResultLocale(locale, k string, args ...interface{}) tele.Resul
 
 	var buf bytes.Buffer
 	if err := lt.template(result.result, locale).Execute(&buf, arg); err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("execute result template", "locale", locale, "result", k, "error", err)
 	}
 
 	var (
@@ -491,72 +552,72 @@ func (lt *Layout) ResultLocale(locale, k string, args ...interface{}) tele.Resul
 	)
 
 	if err := yaml.Unmarshal(data, &base); err != nil {
-		log.Println("telebot/layout:", err)
+		lt.logError("unmarshal result", "locale", locale, "result", k, "error", err)
 	}
 
 	switch base.Type {
 	case "article":
 		r = &tele.ArticleResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "audio":
 		r = &tele.AudioResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "contact":
 		r = &tele.ContactResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "document":
 		r = &tele.DocumentResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "gif":
 		r = &tele.GifResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "location":
 		r = &tele.LocationResult{ResultBase: base.ResultBase}
 		if err := json.Unmarshal(data, &r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "mpeg4_gif":
 		r = &tele.Mpeg4GifResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "photo":
 		r = &tele.PhotoResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "venue":
 		r = &tele.VenueResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "video":
 		r = &tele.VideoResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "voice":
 		r = &tele.VoiceResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	case "sticker":
 		r = &tele.StickerResult{ResultBase: base.ResultBase}
 		if err := yaml.Unmarshal(data, r); err != nil {
-			log.Println("telebot/layout:", err)
+			lt.logError("unmarshal result", "locale", locale, "result", k, "type", base.Type, "error", err)
 		}
 	default:
-		log.Println("telebot/layout: unsupported inline result type")
+		lt.logError("unsupported result type", "result", k, "type", base.Type)
 		return nil
 	}
 
@@ -567,7 +628,7 @@ func (lt *Layout) ResultLocale(locale, k string, args ...interface{}) tele.Resul
 	if result.Markup != "" {
 		markup := lt.MarkupLocale(locale, result.Markup, args...)
 		if markup == nil {
-			log.Printf("telebot/layout: markup with name %s was not found\n", result.Markup)
+			lt.logError("markup of result not found", "result", k, "markup", result.Markup)
 		} else {
 			r.SetReplyMarkup(markup)
 		}
@@ -583,6 +644,9 @@ func (lt *Layout) template(tmpl *template.Template, locale string) *template.Tem
 	funcs["config"] = lt.String
 	funcs["text"] = func(k string, args ...interface{}) string { return lt.TextLocale(locale, k, args...) }
 	funcs["locale"] = func() string { return locale }
+	funcs["plural"] = func(k string, count interface{}, args ...interface{}) string {
+		return lt.PluralLocale(locale, k, count, args...)
+	}
 
 	return tmpl.Funcs(funcs)
 }
@@ -30,6 +30,11 @@ func TestLayout(t *testing.T) {
 	assert.Equal(t, "TEST", pref.Token)
 	assert.Equal(t, tele.ModeHTML, pref.ParseMode)
 	assert.Equal(t, &tele.LongPoller{}, pref.Poller)
+	assert.Equal(t, &tele.Transport{
+		Proxy:         "socks5://localhost:1080",
+		Timeout:       30 * time.Second,
+		UploadTimeout: 10 * time.Minute,
+	}, pref.Transport)
 	assert.Equal(t, pref, ltfs.Settings())
 
 	assert.ElementsMatch(t, []tele.Command{{
@@ -0,0 +1,104 @@
+package layout
+
+import (
+	"strings"
+	"text/template"
+)
+
+// Missing configures what the texts missing in the locale resolve to.
+// By default, they resolve to an empty string.
+//
+// Usage:
+//
+//	lt.SetMissing(layout.Missing{
+//		Fallback:  "en",
+//		ReturnKey: true,
+//		Report: func(locale, key string) {
+//			missingTexts.WithLabelValues(locale, key).Inc()
+//		},
+//	})
+type Missing struct {
+	// Fallback is the locale the missing texts are taken from.
+	Fallback string
+
+	// ReturnKey makes the key itself the text, if it's missing
+	// in the fallback locale as well.
+	ReturnKey bool
+
+	// Report is called with the locale and the key of every missing text,
+	// including the ones found in the fallback locale afterwards.
+	Report func(locale, key string)
+}
+
+// SetMissing sets the way the missing texts are resolved.
+func (lt *Layout) SetMissing(m Missing) {
+	lt.mu.Lock()
+	lt.missing = m
+	lt.mu.Unlock()
+}
+
+// localeChain returns the locale followed by its language,
+// e.g. pt-BR and pt, which the texts are looked up in.
+func localeChain(locale string) []string {
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		return []string{locale, locale[:i]}
+	}
+	return []string{locale}
+}
+
+// find returns the templates of the first locale in the chain
+// of the given one, which has the text.
+func (lt *Layout) find(locale string, has func(*template.Template) bool) (*template.Template, bool) {
+	locales := lt.load().locales
+	for _, l := range localeChain(locale) {
+		if tmpl, ok := locales[l]; ok && has(tmpl) {
+			return tmpl, true
+		}
+	}
+	return nil, false
+}
+
+// resolveMissing reports the missing text and resolves it according to
+// the Missing settings. The text of the fallback locale is made by render.
+func (lt *Layout) resolveMissing(locale, k string, render func(fallback string) string) string {
+	lt.mu.RLock()
+	m := lt.missing
+	lt.mu.RUnlock()
+
+	if m.Report != nil {
+		m.Report(locale, k)
+	}
+	if m.Fallback != "" && m.Fallback != locale {
+		return render(m.Fallback)
+	}
+	if m.ReturnKey {
+		return k
+	}
+	return ""
+}
+
+// matchLocale returns the presented locale matching the given one,
+// case-insensitively and falling back to its language.
+// Returns an empty string if there is none.
+func (lt *Layout) matchLocale(locale string) string {
+	if locale == "" {
+		return ""
+	}
+
+	normalize := func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, "_", "-"))
+	}
+
+	locales := lt.load().locales
+	for _, want := range localeChain(normalize(locale)) {
+		if _, ok := locales[want]; ok {
+			return want
+		}
+		for l := range locales {
+			if normalize(l) == want {
+				return l
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tele "github.com/irijopa/telebot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLocales(t *testing.T) *Layout {
+	dir := t.TempDir()
+	locales := filepath.Join(dir, "locales")
+	require.NoError(t, os.Mkdir(locales, 0700))
+
+	write := func(path, data string) {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	}
+	write(filepath.Join(dir, "bot.yml"), "settings:\n  locales_dir: "+locales)
+	write(filepath.Join(locales, "en.yml"), "hello: Hello\nbye: Bye\nitems:\n  one: item\n  other: items")
+	write(filepath.Join(locales, "pt.yml"), "hello: Olá\nbye: Tchau")
+	write(filepath.Join(locales, "pt-BR.yml"), "hello: Oi")
+	write(filepath.Join(locales, "ru.yml"), "hello: Привет")
+
+	lt, err := New(filepath.Join(dir, "bot.yml"))
+	require.NoError(t, err)
+	return lt
+}
+
+func TestMissing(t *testing.T) {
+	lt := testLocales(t)
+
+	assert.Equal(t, "Oi", lt.TextLocale("pt-BR", "hello"))
+	assert.Equal(t, "Tchau", lt.TextLocale("pt-BR", "bye"))
+	assert.Equal(t, "", lt.TextLocale("ru", "bye"))
+	assert.Equal(t, "", lt.TextLocale("de", "hello"))
+
+	var missing []string
+	lt.SetMissing(Missing{
+		Fallback: "en",
+		Report: func(locale, key string) {
+			missing = append(missing, locale+":"+key)
+		},
+	})
+
+	assert.Equal(t, "Bye", lt.TextLocale("ru", "bye"))
+	assert.Equal(t, "items", lt.PluralLocale("ru", "items", 5))
+	assert.Equal(t, "", lt.TextLocale("ru", "unknown"))
+	assert.Equal(t, []string{"ru:bye", "ru:items", "ru:unknown", "en:unknown"}, missing)
+
+	lt.SetMissing(Missing{ReturnKey: true})
+	assert.Equal(t, "bye", lt.TextLocale("ru", "bye"))
+	assert.Equal(t, "Tchau", lt.TextLocale("pt-BR", "bye"))
+}
+
+func TestResolveMiddleware(t *testing.T) {
+	lt := testLocales(t)
+
+	b, err := tele.NewBot(tele.Settings{Offline: true})
+	require.NoError(t, err)
+
+	stored := map[int64]string{1: "ru"}
+	chats := map[int64]string{-100: "pt"}
+
+	mw := lt.ResolveMiddleware("en",
+		StoredLocale(func(r tele.Recipient) string {
+			return stored[r.(*tele.User).ID]
+		}),
+		ChatLocale(func(chat *tele.Chat) string {
+			return chats[chat.ID]
+		}),
+		UserLocale(),
+	)
+
+	resolve := func(user *tele.User, chat *tele.Chat) (locale string) {
+		c := b.NewContext(tele.Update{Message: &tele.Message{Sender: user, Chat: chat}})
+		mw(func(c tele.Context) error {
+			locale, _ = lt.Locale(c)
+			return nil
+		})(c)
+		return locale
+	}
+
+	private := &tele.Chat{ID: 2, Type: tele.ChatPrivate}
+	group := &tele.Chat{ID: -100, Type: tele.ChatGroup}
+
+	assert.Equal(t, "ru", resolve(&tele.User{ID: 1, LanguageCode: "pt-br"}, private))
+	assert.Equal(t, "pt", resolve(&tele.User{ID: 2, LanguageCode: "ru"}, group))
+	assert.Equal(t, "pt-BR", resolve(&tele.User{ID: 2, LanguageCode: "pt-br"}, private))
+	assert.Equal(t, "pt", resolve(&tele.User{ID: 2, LanguageCode: "pt-PT"}, private))
+	assert.Equal(t, "en", resolve(&tele.User{ID: 2, LanguageCode: "de"}, private))
+	assert.Equal(t, "en", resolve(nil, nil))
+}
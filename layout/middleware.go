@@ -22,16 +22,87 @@ func (lt *Layout) Middleware(defaultLocale string, localeFunc ...LocaleFunc) tel
 		f = localeFunc[0]
 	}
 
-	return func(next tele.HandlerFunc) tele.HandlerFunc {
-		return func(c tele.Context) error {
-			locale := defaultLocale
-			if f != nil {
-				if l := f(c.Sender()); l != "" {
-					locale = l
-				}
+	return lt.localize(func(c tele.Context) string {
+		if f != nil {
+			if l := f(c.Sender()); l != "" {
+				return l
+			}
+		}
+		return defaultLocale
+	})
+}
+
+// LocaleResolver returns the locale of the update, or an empty string
+// if it has no idea, so the next resolver in the chain is asked.
+type LocaleResolver func(tele.Context) string
+
+// StoredLocale resolves the locale preferred by the sender,
+// e.g. the one stored in the database.
+func StoredLocale(f LocaleFunc) LocaleResolver {
+	return func(c tele.Context) string {
+		if c.Sender() == nil {
+			return ""
+		}
+		return f(c.Sender())
+	}
+}
+
+// UserLocale resolves the language of the sender's Telegram client.
+func UserLocale() LocaleResolver {
+	return func(c tele.Context) string {
+		if c.Sender() == nil {
+			return ""
+		}
+		return c.Sender().LanguageCode
+	}
+}
+
+// ChatLocale resolves the locale set for the group or the channel.
+// The private chats are skipped.
+func ChatLocale(f func(*tele.Chat) string) LocaleResolver {
+	return func(c tele.Context) string {
+		chat := c.Chat()
+		if chat == nil || chat.Type == tele.ChatPrivate {
+			return ""
+		}
+		return f(chat)
+	}
+}
+
+// ResolveMiddleware builds a telebot middleware to make localization work,
+// which resolves the locale by the chain of resolvers. The first locale
+// presented in the layout wins, the regional ones, e.g. pt-BR, fall back
+// to their language, e.g. pt, if it's missing. If none is resolved,
+// the default locale is used.
+//
+// Usage:
+//
+//	b.Use(lt.ResolveMiddleware("en",
+//		layout.StoredLocale(func(r tele.Recipient) string {
+//			loc, _ := db.UserLocale(r.Recipient())
+//			return loc
+//		}),
+//		layout.ChatLocale(func(chat *tele.Chat) string {
+//			loc, _ := db.ChatLocale(chat.ID)
+//			return loc
+//		}),
+//		layout.UserLocale(),
+//	))
+func (lt *Layout) ResolveMiddleware(defaultLocale string, resolvers ...LocaleResolver) tele.MiddlewareFunc {
+	return lt.localize(func(c tele.Context) string {
+		for _, resolve := range resolvers {
+			if l := lt.matchLocale(resolve(c)); l != "" {
+				return l
 			}
+		}
+		return defaultLocale
+	})
+}
 
-			lt.SetLocale(c, locale)
+func (lt *Layout) localize(resolve func(tele.Context) string) tele.MiddlewareFunc {
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			lt.SetLocale(c, resolve(c))
 
 			defer func() {
 				lt.mu.Lock()
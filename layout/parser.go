@@ -17,6 +17,7 @@ type Settings struct {
 	URL     string
 	Token   string
 	Updates int
+	Local   bool
 
 	LocalesDir string `yaml:"locales_dir"`
 	TokenEnv   string `yaml:"token_env"`
@@ -24,9 +25,21 @@ type Settings struct {
 
 	Webhook    *tele.Webhook    `yaml:"webhook"`
 	LongPoller *tele.LongPoller `yaml:"long_poller"`
+	Transport  *tele.Transport  `yaml:"transport"`
 }
 
+// UnmarshalYAML parses the layout and replaces the current one.
 func (lt *Layout) UnmarshalYAML(data []byte) error {
+	d, err := lt.parse(data)
+	if err != nil {
+		return err
+	}
+	lt.store(d)
+	return nil
+}
+
+// parse parses the layout, including the locales.
+func (lt *Layout) parse(data []byte) (*layoutData, error) {
 	var aux struct {
 		Settings *Settings
 		Config   map[string]interface{}
@@ -37,37 +50,41 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 		Locales  map[string]map[string]string
 	}
 	if err := yaml.Unmarshal(data, &aux); err != nil {
-		return err
+		return nil, err
 	}
 
 	v := viper.New()
 	if err := v.MergeConfigMap(aux.Config); err != nil {
-		return err
+		return nil, err
 	}
 
-	lt.Config = Config{v: v}
-	lt.commands = aux.Commands
+	d := &layoutData{
+		config:   v,
+		commands: aux.Commands,
+	}
 
 	if pref := aux.Settings; pref != nil {
-		lt.pref = &tele.Settings{
+		d.pref = &tele.Settings{
 			URL:       pref.URL,
 			Token:     pref.Token,
 			Updates:   pref.Updates,
 			ParseMode: pref.ParseMode,
+			Local:     pref.Local,
+			Transport: pref.Transport,
 		}
 
 		if pref.TokenEnv != "" {
-			lt.pref.Token = os.Getenv(pref.TokenEnv)
+			d.pref.Token = os.Getenv(pref.TokenEnv)
 		}
 
 		if pref.Webhook != nil {
-			lt.pref.Poller = pref.Webhook
+			d.pref.Poller = pref.Webhook
 		} else if pref.LongPoller != nil {
-			lt.pref.Poller = pref.LongPoller
+			d.pref.Poller = pref.LongPoller
 		}
 	}
 
-	lt.buttons = make(map[string]Button, len(aux.Buttons))
+	d.buttons = make(map[string]Button, len(aux.Buttons))
 	for _, item := range aux.Buttons {
 		k, v := item.Key.(string), item.Value
 
@@ -75,7 +92,7 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 
 		if v, ok := v.(string); ok {
 			btn := tele.Btn{Text: v}
-			lt.buttons[k] = Button{Btn: btn}
+			d.buttons[k] = Button{Btn: btn}
 			continue
 		}
 
@@ -83,12 +100,12 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 
 		data, err := yaml.MarshalWithOptions(v, yaml.JSON())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		var btn Button
 		if err := yaml.Unmarshal(data, &btn); err != nil {
-			return err
+			return nil, err
 		}
 
 		if !btn.IsReply && btn.Data != nil {
@@ -101,20 +118,20 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 			} else if s, ok := btn.Data.(string); ok {
 				btn.Btn.Data = s
 			} else {
-				return fmt.Errorf("telebot/layout: invalid callback_data for %s button", k)
+				return nil, fmt.Errorf("telebot/layout: invalid callback_data for %s button", k)
 			}
 		}
 
-		lt.buttons[k] = btn
+		d.buttons[k] = btn
 	}
 
-	lt.markups = make(map[string]Markup, len(aux.Markups))
+	d.markups = make(map[string]Markup, len(aux.Markups))
 	for _, item := range aux.Markups {
 		k, v := item.Key.(string), item.Value
 
 		data, err := yaml.Marshal(v)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		var shortenedMarkup [][]string
@@ -125,9 +142,9 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 			for i, btns := range shortenedMarkup {
 				row := make([]Button, len(btns))
 				for j, btn := range btns {
-					b, ok := lt.buttons[btn]
+					b, ok := d.buttons[btn]
 					if !ok {
-						return fmt.Errorf("telebot/layout: no %s button for %s markup", btn, k)
+						return nil, fmt.Errorf("telebot/layout: no %s button for %s markup", btn, k)
 					}
 					row[j] = b
 				}
@@ -136,12 +153,12 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 
 			data, err := yaml.Marshal(kb)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			tmpl, err := template.New(k).Funcs(lt.funcs).Parse(string(data))
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			markup := Markup{keyboard: tmpl}
@@ -158,12 +175,12 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 					if markup.inline == nil {
 						markup.inline = &inline
 					} else if *markup.inline != inline {
-						return fmt.Errorf("telebot/layout: mixed reply and inline buttons in %s markup", k)
+						return nil, fmt.Errorf("telebot/layout: mixed reply and inline buttons in %s markup", k)
 					}
 				}
 			}
 
-			lt.markups[k] = markup
+			d.markups[k] = markup
 		} else {
 			// 2. Extended reply markup
 
@@ -172,69 +189,72 @@ func (lt *Layout) UnmarshalYAML(data []byte) error {
 				Keyboard [][]string `yaml:"keyboard"`
 			}
 			if err := yaml.Unmarshal(data, &markup); err != nil {
-				return err
+				return nil, err
 			}
 
 			kb := make([][]tele.ReplyButton, len(markup.Keyboard))
 			for i, btns := range markup.Keyboard {
 				row := make([]tele.ReplyButton, len(btns))
 				for j, btn := range btns {
-					row[j] = *lt.buttons[btn].Reply()
+					row[j] = *d.buttons[btn].Reply()
 				}
 				kb[i] = row
 			}
 
 			data, err := yaml.Marshal(kb)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			tmpl, err := template.New(k).Funcs(lt.funcs).Parse(string(data))
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			markup.inline = new(bool)
 			markup.keyboard = tmpl
-			lt.markups[k] = markup.Markup
+			d.markups[k] = markup.Markup
 		}
 	}
 
-	lt.results = make(map[string]Result, len(aux.Results))
+	d.results = make(map[string]Result, len(aux.Results))
 	for _, item := range aux.Results {
 		k, v := item.Key.(string), item.Value
 
 		data, err := yaml.Marshal(v)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		tmpl, err := template.New(k).Funcs(lt.funcs).Parse(string(data))
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		var result Result
 		if err := yaml.Unmarshal(data, &result); err != nil {
-			return err
+			return nil, err
 		}
 
 		result.result = tmpl
-		lt.results[k] = result
+		d.results[k] = result
 	}
 
 	if aux.Locales == nil {
 		if aux.Settings.LocalesDir == "" {
 			aux.Settings.LocalesDir = "locales"
 		}
-		return lt.parseLocales(aux.Settings.LocalesDir)
+		d.localesDir = aux.Settings.LocalesDir
+		if err := lt.parseLocales(d, d.localesDir); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return d, nil
 }
 
-func (lt *Layout) parseLocales(dir string) error {
-	lt.locales = make(map[string]*template.Template)
+func (lt *Layout) parseLocales(d *layoutData, dir string) error {
+	d.locales = make(map[string]*template.Template)
 
 	return filepath.Walk(dir, func(path string, fi os.FileInfo, _ error) error {
 		if fi == nil || fi.IsDir() {
@@ -267,7 +287,7 @@ func (lt *Layout) parseLocales(dir string) error {
 			}
 		}
 
-		lt.locales[name] = tmpl
+		d.locales[name] = tmpl
 		return nil
 	})
 }
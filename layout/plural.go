@@ -0,0 +1,289 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+
+	tele "github.com/irijopa/telebot"
+)
+
+// Plural categories as defined by CLDR.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralRule returns the plural category of the count for the language.
+// The count is always non-negative.
+type PluralRule func(n int64) string
+
+// pluralRules are the built-in rules for the integer counts, looked up
+// by the locale first, then by its language. The languages missing here
+// follow the English one.
+var pluralRules = map[string]PluralRule{
+	"en": pluralOne,
+	"de": pluralOne,
+	"es": pluralOne,
+	"it": pluralOne,
+	"nl": pluralOne,
+
+	"fr":    pluralZeroOne,
+	"pt":    pluralZeroOne,
+	"pt-pt": pluralOne,
+
+	"ru": pluralEastSlavic,
+	"uk": pluralEastSlavic,
+	"be": pluralEastSlavic,
+
+	"pl": pluralPolish,
+	"cs": pluralCzech,
+	"sk": pluralCzech,
+	"ar": pluralArabic,
+
+	"ja": pluralNone,
+	"ko": pluralNone,
+	"zh": pluralNone,
+	"vi": pluralNone,
+	"th": pluralNone,
+	"id": pluralNone,
+}
+
+// pluralIntegers are the built-in rules applied to the integer part
+// of the fractional counts, e.g. 1.5 is one in French. The fractional
+// counts fall into the other category for the rest.
+var pluralIntegers = map[string]bool{
+	"fr": true,
+	"pt": true,
+}
+
+func pluralOne(n int64) string {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralZeroOne(n int64) string {
+	if n == 0 || n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralEastSlavic(n int64) string {
+	switch n10, n100 := n%10, n%100; {
+	case n10 == 1 && n100 != 11:
+		return PluralOne
+	case n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralPolish(n int64) string {
+	switch n10, n100 := n%10, n%100; {
+	case n == 1:
+		return PluralOne
+	case n10 >= 2 && n10 <= 4 && (n100 < 12 || n100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralCzech(n int64) string {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n >= 2 && n <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+func pluralArabic(n int64) string {
+	switch n100 := n % 100; {
+	case n == 0:
+		return PluralZero
+	case n == 1:
+		return PluralOne
+	case n == 2:
+		return PluralTwo
+	case n100 >= 3 && n100 <= 10:
+		return PluralFew
+	case n100 >= 11:
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+func pluralNone(int64) string {
+	return PluralOther
+}
+
+// SetPluralRule sets the plural rule of the locale, overriding
+// the built-in one. The rule of the locale, e.g. pt-BR, is looked up
+// first, then the one of its language, e.g. pt. The fractional counts
+// fall into the other category with the rules set here.
+func (lt *Layout) SetPluralRule(locale string, rule PluralRule) {
+	lt.mu.Lock()
+	if lt.rules == nil {
+		lt.rules = make(map[string]PluralRule)
+	}
+	lt.rules[locale] = rule
+	lt.mu.Unlock()
+}
+
+// pluralRule returns the rule of the locale and whether it applies
+// to the integer part of the fractional counts.
+func (lt *Layout) pluralRule(locale string) (PluralRule, bool) {
+	tag := strings.Replace(strings.ToLower(locale), "_", "-", -1)
+	lang := tag
+	if i := strings.IndexByte(lang, '-'); i >= 0 {
+		lang = lang[:i]
+	}
+
+	lt.mu.RLock()
+	defer lt.mu.RUnlock()
+
+	for _, k := range []string{locale, lang} {
+		if rule, ok := lt.rules[k]; ok {
+			return rule, false
+		}
+	}
+	for _, k := range []string{tag, lang} {
+		if rule, ok := pluralRules[k]; ok {
+			return rule, pluralIntegers[k]
+		}
+	}
+	return pluralOne, false
+}
+
+// pluralCategory returns the plural category of the count in the locale.
+// Fractional counts fall into the other category, unless the built-in
+// rule of the locale categorizes them by the integer part.
+func (lt *Layout) pluralCategory(locale string, count interface{}) (string, error) {
+	var (
+		n        int64
+		fraction bool
+	)
+	switch v := count.(type) {
+	case int:
+		n = int64(v)
+	case int8:
+		n = int64(v)
+	case int16:
+		n = int64(v)
+	case int32:
+		n = int64(v)
+	case int64:
+		n = v
+	case uint:
+		n = int64(v)
+	case uint8:
+		n = int64(v)
+	case uint16:
+		n = int64(v)
+	case uint32:
+		n = int64(v)
+	case uint64:
+		n = int64(v)
+	case float32:
+		return lt.pluralCategory(locale, float64(v))
+	case float64:
+		fraction = v != math.Trunc(v)
+		n = int64(v)
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", fmt.Errorf("telebot/layout: invalid plural count %q", v)
+		}
+		return lt.pluralCategory(locale, f)
+	default:
+		return "", fmt.Errorf("telebot/layout: invalid plural count of %T type", count)
+	}
+
+	if n < 0 {
+		n = -n
+	}
+
+	rule, integer := lt.pluralRule(locale)
+	if fraction && !integer {
+		return PluralOther, nil
+	}
+	return rule(n), nil
+}
+
+// Plural returns a text in the plural form of the count,
+// which locale is dependent on the context.
+// The given optional argument will be passed to the template engine,
+// otherwise the count itself is passed.
+//
+// The forms are the keys of the text named after the CLDR plural
+// categories: zero, one, two, few, many and other. The other form is
+// used when the one of the category is missing.
+//
+// Example of ru.yml:
+//
+//	messages:
+//		one: '{{ . }} сообщение'
+//		few: '{{ . }} сообщения'
+//		many: '{{ . }} сообщений'
+//		other: '{{ . }} сообщения'
+//
+// Usage:
+//
+//	lt.Plural(c, "messages", 21) // 21 сообщение
+//
+// The same is available in the templates with the plural function:
+//
+//	inbox: 'You have {{ plural "messages" .Count }}.'
+func (lt *Layout) Plural(c tele.Context, k string, count interface{}, args ...interface{}) string {
+	locale, ok := lt.Locale(c)
+	if !ok {
+		return ""
+	}
+
+	return lt.PluralLocale(locale, k, count, args...)
+}
+
+// PluralLocale returns a localized text in the plural form of the count.
+// See Plural for more details.
+func (lt *Layout) PluralLocale(locale, k string, count interface{}, args ...interface{}) string {
+	category, err := lt.pluralCategory(locale, count)
+	if err != nil {
+		lt.logError("invalid plural count", "locale", locale, "key", k, "error", err)
+		return ""
+	}
+
+	tmpl, ok := lt.find(locale, func(tmpl *template.Template) bool {
+		return tmpl.Lookup(k+"."+category) != nil || tmpl.Lookup(k+"."+PluralOther) != nil
+	})
+	if !ok {
+		return lt.resolveMissing(locale, k, func(fallback string) string {
+			return lt.PluralLocale(fallback, k, count, args...)
+		})
+	}
+
+	key := k + "." + category
+	if tmpl.Lookup(key) == nil {
+		key = k + "." + PluralOther
+	}
+
+	arg := count
+	if len(args) > 0 {
+		arg = args[0]
+	}
+
+	return lt.TextLocale(locale, key, arg)
+}
@@ -0,0 +1,114 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		rule  PluralRule
+		cases map[int64]string
+	}{
+		{pluralOne, map[int64]string{0: PluralOther, 1: PluralOne, 2: PluralOther, 21: PluralOther}},
+		{pluralZeroOne, map[int64]string{0: PluralOne, 1: PluralOne, 2: PluralOther}},
+		{pluralEastSlavic, map[int64]string{
+			0: PluralMany, 1: PluralOne, 2: PluralFew, 5: PluralMany, 11: PluralMany,
+			12: PluralMany, 21: PluralOne, 22: PluralFew, 111: PluralMany, 1001: PluralOne,
+		}},
+		{pluralPolish, map[int64]string{
+			0: PluralMany, 1: PluralOne, 2: PluralFew, 5: PluralMany, 12: PluralMany,
+			21: PluralMany, 22: PluralFew, 104: PluralFew,
+		}},
+		{pluralCzech, map[int64]string{1: PluralOne, 3: PluralFew, 5: PluralOther}},
+		{pluralArabic, map[int64]string{
+			0: PluralZero, 1: PluralOne, 2: PluralTwo, 3: PluralFew, 11: PluralMany,
+			100: PluralOther, 103: PluralFew,
+		}},
+		{pluralNone, map[int64]string{1: PluralOther, 2: PluralOther}},
+	}
+	for _, tt := range tests {
+		for n, want := range tt.cases {
+			assert.Equal(t, want, tt.rule(n), n)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	dir := t.TempDir()
+	locales := filepath.Join(dir, "locales")
+	require.NoError(t, os.Mkdir(locales, 0700))
+
+	write := func(path, data string) {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	}
+	write(filepath.Join(dir, "bot.yml"), "settings:\n  locales_dir: "+locales)
+	write(filepath.Join(locales, "ru.yml"), `
+messages:
+  one: '{{ . }} сообщение'
+  few: '{{ . }} сообщения'
+  many: '{{ . }} сообщений'
+  other: '{{ . }} сообщения'
+inbox: 'У вас {{ plural "messages" .Count }}.'
+`)
+	write(filepath.Join(locales, "pl.yml"), `
+messages:
+  one: '{{ . }} wiadomość'
+  few: '{{ . }} wiadomości'
+  other: '{{ . }} wiadomości'
+minutes:
+  one: 'minuta'
+  few: '{{ .N }} minuty'
+  many: '{{ .N }} minut'
+`)
+
+	lt, err := New(filepath.Join(dir, "bot.yml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "1 сообщение", lt.PluralLocale("ru", "messages", 1))
+	assert.Equal(t, "3 сообщения", lt.PluralLocale("ru", "messages", int64(3)))
+	assert.Equal(t, "11 сообщений", lt.PluralLocale("ru", "messages", uint(11)))
+	assert.Equal(t, "21 сообщение", lt.PluralLocale("ru", "messages", "21"))
+	assert.Equal(t, "1.5 сообщения", lt.PluralLocale("ru", "messages", 1.5))
+	assert.Equal(t, "У вас 25 сообщений.", lt.TextLocale("ru", "inbox", struct{ Count int }{25}))
+
+	// the missing form falls back to the other one
+	assert.Equal(t, "5 wiadomości", lt.PluralLocale("pl", "messages", 5))
+	assert.Equal(t, "22 minuty", lt.PluralLocale("pl", "minutes", 22, struct{ N int }{22}))
+	assert.Equal(t, "", lt.PluralLocale("pl", "messages", struct{}{}))
+	assert.Equal(t, "", lt.PluralLocale("de", "messages", 1))
+
+	// the integer part of fractions is categorized in French and Portuguese,
+	// but not in European Portuguese, where zero is other too
+	for _, tt := range []struct {
+		locale string
+		count  interface{}
+		want   string
+	}{
+		{"fr", 0, PluralOne},
+		{"fr", 1.5, PluralOne},
+		{"fr", "2.5", PluralOther},
+		{"pt", 0, PluralOne},
+		{"pt_BR", 0.5, PluralOne},
+		{"pt-PT", 0, PluralOther},
+		{"pt-PT", 1, PluralOne},
+		{"pt_PT", 1.5, PluralOther},
+		{"en", 1.5, PluralOther},
+	} {
+		category, err := lt.pluralCategory(tt.locale, tt.count)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, category, tt.locale, tt.count)
+	}
+
+	lt.SetPluralRule("pl", func(int64) string { return PluralOne })
+	assert.Equal(t, "minuta", lt.PluralLocale("pl", "minutes", 5))
+
+	en, err := New("example.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "You have 1 message.", en.TextLocale("en", "inbox", 1))
+	assert.Equal(t, "2 messages", en.Default("en").Plural("messages", 2))
+}
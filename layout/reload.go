@@ -0,0 +1,98 @@
+package layout
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reload parses the layout file and the locales again and replaces
+// the texts, buttons, markups, results and config at once. If the new
+// version fails to parse, the current one is kept and the error is returned.
+//
+// The settings are reloaded as well, but since the bot is already
+// created, they take effect only for the bots created afterwards.
+func (lt *Layout) Reload() error {
+	data, err := lt.read()
+	if err != nil {
+		return err
+	}
+
+	d, err := lt.parse(data)
+	if err != nil {
+		return err
+	}
+
+	lt.store(d)
+	return nil
+}
+
+// Watch polls the layout file and the files in the locales directory
+// every interval and reloads the layout once any of them changes,
+// until stop is closed. The errors of reloading are logged and the
+// current version is kept until the files are fixed.
+//
+// Usage:
+//
+//	stop := make(chan struct{})
+//	go lt.Watch(time.Second, stop)
+func (lt *Layout) Watch(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := lt.stamp()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		stamp := lt.stamp()
+		if stamp == last {
+			continue
+		}
+		last = stamp
+
+		if err := lt.Reload(); err != nil {
+			lt.logError("reload layout", "path", lt.path, "locales_dir", lt.load().localesDir, "error", err)
+		}
+	}
+}
+
+// stamp describes the state of the layout files, so any change
+// of their names, sizes or modification times changes the stamp.
+func (lt *Layout) stamp() string {
+	var stamps []string
+	add := func(path string, fi fs.FileInfo, err error) {
+		if err != nil {
+			stamps = append(stamps, path+" "+err.Error())
+			return
+		}
+		stamps = append(stamps, fmt.Sprint(path, " ", fi.Size(), " ", fi.ModTime().UnixNano()))
+	}
+
+	if lt.fsys != nil {
+		fi, err := fs.Stat(lt.fsys, lt.path)
+		add(lt.path, fi, err)
+	} else {
+		fi, err := os.Stat(lt.path)
+		add(lt.path, fi, err)
+	}
+
+	if dir := lt.load().localesDir; dir != "" {
+		filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil || !fi.IsDir() {
+				add(path, fi, err)
+			}
+			return nil
+		})
+	}
+
+	sort.Strings(stamps)
+	return strings.Join(stamps, "\n")
+}
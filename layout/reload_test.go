@@ -0,0 +1,105 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bot.yml")
+	locale := filepath.Join(dir, "locales", "en.yml")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "locales"), 0700))
+
+	write := func(path, data string) {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	}
+
+	write(path, `
+settings:
+  locales_dir: `+filepath.Join(dir, "locales")+`
+config:
+  limit: 1
+buttons:
+  help: Help
+markups:
+  menu:
+    - [help]
+`)
+	write(locale, `start: Hi!`)
+
+	lt, err := New(path)
+	require.NoError(t, err)
+	dlt := lt.Default("en")
+
+	assert.Equal(t, "Hi!", lt.TextLocale("en", "start"))
+	assert.Equal(t, 1, dlt.Int("limit"))
+	assert.Equal(t, "Help", lt.ButtonLocale("en", "help").Text)
+
+	write(path, `
+settings:
+  locales_dir: `+filepath.Join(dir, "locales")+`
+config:
+  limit: 2
+buttons:
+  help: Support
+markups:
+  menu:
+    - [help]
+`)
+	write(locale, `start: Hello!`)
+
+	require.NoError(t, lt.Reload())
+	assert.Equal(t, "Hello!", lt.TextLocale("en", "start"))
+	assert.Equal(t, 2, dlt.Int("limit"))
+	assert.Equal(t, "Support", lt.ButtonLocale("en", "help").Text)
+
+	// invalid versions are rejected
+	write(path, `
+settings:
+  locales_dir: `+filepath.Join(dir, "locales")+`
+config:
+  limit: 3
+markups:
+  menu:
+    - [unknown]
+`)
+	assert.Error(t, lt.Reload())
+	assert.Equal(t, 2, lt.Int("limit"))
+	assert.Equal(t, "Support", lt.ButtonLocale("en", "help").Text)
+
+	write(locale, `start: {{ .Broken`)
+	assert.Error(t, lt.Reload())
+	assert.Equal(t, "Hello!", lt.TextLocale("en", "start"))
+
+	t.Run("watch", func(t *testing.T) {
+		write(path, `
+settings:
+  locales_dir: `+filepath.Join(dir, "locales")+`
+`)
+		write(locale, `start: Hey!`)
+		require.NoError(t, lt.Reload())
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			lt.Watch(10*time.Millisecond, stop)
+			close(done)
+		}()
+
+		time.Sleep(30 * time.Millisecond)
+		write(locale, `start: Good day!`)
+
+		assert.Eventually(t, func() bool {
+			return lt.TextLocale("en", "start") == "Good day!"
+		}, time.Second, 10*time.Millisecond)
+
+		close(stop)
+		<-done
+	})
+}
@@ -0,0 +1,78 @@
+package telebot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate of Bot API requests. It's set by Settings.Limiter
+// and may be shared by several bots, see Manager.
+type Limiter interface {
+	// Wait blocks until the request is allowed or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// NewRateLimiter returns a token bucket Limiter allowing rate requests
+// per second on average with bursts of at most burst requests.
+// A non-positive rate means no limit.
+func NewRateLimiter(rate float64, burst int) Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token and returns zero, or returns
+// the time to wait for the next token to be available.
+func (l *rateLimiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
@@ -0,0 +1,100 @@
+package telebot
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// Maximum file sizes allowed by the Bot API server.
+const (
+	CloudMaxDownloadSize = 20 << 20
+	CloudMaxUploadSize   = 50 << 20
+	LocalMaxUploadSize   = 2000 << 20
+)
+
+// Local reports whether the bot works with a local Bot API server.
+// See Settings.Local.
+func (b *Bot) Local() bool {
+	return b.local
+}
+
+// MaxUploadSize returns the maximum size of a file the bot can upload.
+func (b *Bot) MaxUploadSize() int64 {
+	if b.local {
+		return LocalMaxUploadSize
+	}
+	return CloudMaxUploadSize
+}
+
+// MaxDownloadSize returns the maximum size of a file the bot can download.
+// Zero means there is no limit, which is the case of a local server.
+func (b *Bot) MaxDownloadSize() int64 {
+	if b.local {
+		return 0
+	}
+	return CloudMaxDownloadSize
+}
+
+// MoveTo moves the bot to another Bot API server. If the bot is currently
+// served by the cloud server, it logs out from it, otherwise it closes the
+// current local instance. Then it switches to the given URL.
+//
+// Note, after logging out, the bot can't log in back to the cloud
+// server for 10 minutes.
+//
+// Usage:
+//
+//	// from the cloud to a local server
+//	err := b.MoveTo("http://localhost:8081", true)
+func (b *Bot) MoveTo(url string, local bool) error {
+	var err error
+	if b.local {
+		_, err = b.Close()
+	} else {
+		_, err = b.Logout()
+	}
+	if err != nil {
+		return err
+	}
+
+	b.URL = url
+	b.local = local
+	return nil
+}
+
+// localFiles passes on-disk files as file:// URIs, which a local server
+// reads right from its file system, so there is no need to stream them.
+// The attach:// references to such files in the params are replaced too.
+func localFiles(files map[string]File, params map[string]string) error {
+	for name, f := range files {
+		if f.InCloud() || f.FileURL != "" || !f.OnDisk() {
+			continue
+		}
+
+		path, err := filepath.Abs(f.FileLocal)
+		if err != nil {
+			return wrapError(err)
+		}
+
+		uri := "file://" + filepath.ToSlash(path)
+
+		// params like media hold JSON with quoted references
+		from, _ := json.Marshal("attach://" + name)
+		to, _ := json.Marshal(uri)
+
+		var attached bool
+		for k, v := range params {
+			if strings.Contains(v, string(from)) {
+				params[k] = strings.ReplaceAll(v, string(from), string(to))
+				attached = true
+			}
+		}
+		if !attached {
+			params[name] = uri
+		}
+
+		delete(files, name)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package telebot
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+
+	var params map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		params = nil
+		json.NewDecoder(r.Body).Decode(&params)
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getFile"):
+			w.Write([]byte(`{"ok":true,"result":{"file_id":"1","file_path":` + strconvQuote(path) + `}}`))
+		case strings.HasSuffix(r.URL.Path, "/sendPhoto"):
+			w.Write([]byte(`{"ok":true,"result":{"photo":[{"file_id":"1"}]}}`))
+		case strings.HasSuffix(r.URL.Path, "/sendMediaGroup"):
+			w.Write([]byte(`{"ok":true,"result":[{"photo":[{"file_id":"1"}]},{"photo":[{"file_id":"2"}]}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true, Local: true})
+	require.NoError(t, err)
+
+	assert.True(t, b.Local())
+	assert.Equal(t, int64(LocalMaxUploadSize), b.MaxUploadSize())
+	assert.Zero(t, b.MaxDownloadSize())
+
+	_, err = b.Send(&Chat{ID: 1}, &Photo{File: FromDisk(path)})
+	require.NoError(t, err)
+	assert.Equal(t, "file://"+filepath.ToSlash(path), params["photo"])
+
+	_, err = b.SendAlbum(&Chat{ID: 1}, Album{
+		&Photo{File: FromDisk(path)},
+		&Photo{File: FromURL("https://example.com/a.jpg")},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, params["media"], `"media":"file://`+filepath.ToSlash(path)+`"`)
+	assert.NotContains(t, params, "0")
+
+	r, err := b.File(&File{FileID: "1"})
+	require.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "data", string(data))
+}
+
+func strconvQuote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
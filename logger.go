@@ -0,0 +1,132 @@
+package telebot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// LogLevel is the severity of a log record.
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "level(" + strconv.Itoa(int(l)) + ")"
+}
+
+// Logger is a leveled logger with structured fields.
+// Fields go in key-value pairs, for example:
+//
+//	logger.Log(tele.LevelInfo, "sent request",
+//		"method", "sendMessage",
+//		"latency", time.Second,
+//	)
+//
+// Telebot uses the following keys: update_id, chat_id, method,
+// latency and error.
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...interface{})
+}
+
+// LoggerFunc is an adapter to use ordinary functions as Logger.
+type LoggerFunc func(level LogLevel, msg string, fields ...interface{})
+
+// Log calls f(level, msg, fields...).
+func (f LoggerFunc) Log(level LogLevel, msg string, fields ...interface{}) {
+	f(level, msg, fields...)
+}
+
+// NopLogger discards all the records.
+var NopLogger Logger = LoggerFunc(func(LogLevel, string, ...interface{}) {})
+
+// NewStdLogger returns a Logger, which writes the records to the
+// standard library logger in logfmt format, e.g.:
+//
+//	level=error msg="handler failed" update_id=1 chat_id=2 error="telebot: ..."
+//
+// The records below the min level are skipped.
+// If l is nil, log.Default() is used.
+func NewStdLogger(l *log.Logger, min LogLevel) Logger {
+	if l == nil {
+		l = log.Default()
+	}
+	return &stdLogger{l: l, min: min}
+}
+
+type stdLogger struct {
+	l   *log.Logger
+	min LogLevel
+}
+
+func (s *stdLogger) Log(level LogLevel, msg string, fields ...interface{}) {
+	if level < s.min {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("level=")
+	b.WriteString(level.String())
+	b.WriteString(" msg=")
+	b.WriteString(logfmtValue(msg))
+
+	for i := 0; i < len(fields); i += 2 {
+		b.WriteByte(' ')
+		b.WriteString(fmt.Sprint(fields[i]))
+		b.WriteByte('=')
+		if i+1 < len(fields) {
+			b.WriteString(logfmtValue(fields[i+1]))
+		} else {
+			b.WriteString("(MISSING)")
+		}
+	}
+
+	s.l.Print(b.String())
+}
+
+// logfmtValue formats v, quoting it when needed.
+func logfmtValue(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// logFields returns the fields describing the update of c.
+func logFields(c Context) []interface{} {
+	if c == nil {
+		return nil
+	}
+
+	fields := []interface{}{"update_id", c.Update().ID}
+	if chat := c.Chat(); chat != nil {
+		fields = append(fields, "chat_id", chat.ID)
+	}
+	return fields
+}
@@ -0,0 +1,51 @@
+package telebot
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logRecord struct {
+	level  LogLevel
+	msg    string
+	fields []interface{}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0), LevelInfo)
+
+	logger.Log(LevelDebug, "skipped")
+	assert.Empty(t, buf.String())
+
+	logger.Log(LevelError, "handler failed", "update_id", 1, "error", errors.New("bad request"), "odd")
+	assert.Equal(t, `level=error msg="handler failed" update_id=1 error="bad request" odd=(MISSING)`+"\n", buf.String())
+
+	assert.Equal(t, "level(7)", LogLevel(7).String())
+}
+
+func TestBotLogger(t *testing.T) {
+	var records []logRecord
+	logger := LoggerFunc(func(level LogLevel, msg string, fields ...interface{}) {
+		records = append(records, logRecord{level, msg, fields})
+	})
+
+	b, err := NewBot(Settings{Synchronous: true, Offline: true, Logger: logger})
+	require.NoError(t, err)
+	assert.NotNil(t, b.Logger())
+
+	b.Handle(OnAny, func(c Context) error {
+		return errors.New("oops")
+	})
+	b.ProcessUpdate(Update{ID: 1, Message: &Message{Text: "hi", Chat: &Chat{ID: 2}}})
+
+	require.Len(t, records, 1)
+	assert.Equal(t, LevelError, records[0].level)
+	assert.Equal(t, "handler failed", records[0].msg)
+	assert.Equal(t, []interface{}{"update_id", 1, "chat_id", int64(2), "error", errors.New("oops")}, records[0].fields)
+}
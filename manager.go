@@ -0,0 +1,306 @@
+package telebot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Manager runs multiple bots in one process. The bots share the HTTP
+// client and the rate limiter, and are started and stopped together.
+// Bots can be added and removed at any time, the ones added to a started
+// manager are started right away, so set them up within Add.
+//
+// Example:
+//
+//	m := &tele.Manager{Limiter: tele.NewRateLimiter(30, 30)}
+//
+//	_, err := m.Add("customer1", tele.Settings{Token: "..."}, func(b *tele.Bot) {
+//		b.Handle("/start", onStart)
+//	})
+//	if err != nil {
+//		return err
+//	}
+//
+//	m.Start()
+//	defer m.Stop()
+//
+//	http.Handle("/health", m)
+type Manager struct {
+	// Client is shared by the bots, which settings don't have their
+	// own Client or Transport. Defaulted to the client with DefaultTimeout.
+	Client *http.Client
+
+	// Limiter is shared by the bots, which settings don't have
+	// their own one. By default, the requests aren't limited.
+	Limiter Limiter
+
+	mu      sync.Mutex
+	bots    map[string]*managedBot
+	started bool
+}
+
+type managedBot struct {
+	// errors goes first to be 64-bit aligned for atomic operations.
+	errors int64
+
+	name string
+	bot  *Bot
+
+	mu        sync.Mutex
+	running   bool
+	done      chan struct{}
+	lastError error
+	errorTime time.Time
+}
+
+// BotHealth is the state of the bot run by Manager.
+type BotHealth struct {
+	Name      string `json:"name"`
+	Username  string `json:"username"`
+	Running   bool   `json:"running"`
+	Errors    int64  `json:"errors"`
+	LastError string `json:"last_error,omitempty"`
+	ErrorDate int64  `json:"last_error_date,omitempty"`
+}
+
+// Add creates the bot with the given settings and registers it under
+// the name, which must be unique within the manager. The errors passed
+// to the OnError callback of the bot are counted in its BotHealth.
+//
+// The setup functions are called with the bot before it's started,
+// register the handlers and middleware there: the bot added to
+// a started manager receives the updates as soon as Add returns.
+func (m *Manager) Add(name string, pref Settings, setup ...func(*Bot)) (*Bot, error) {
+	m.mu.Lock()
+	if m.Client == nil {
+		m.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if pref.Client == nil && pref.Transport == nil {
+		pref.Client = m.Client
+	}
+	if pref.Limiter == nil {
+		pref.Limiter = m.Limiter
+	}
+	_, exists := m.bots[name]
+	m.mu.Unlock()
+
+	if exists {
+		return nil, fmt.Errorf("telebot: bot %s is already registered", name)
+	}
+
+	b, err := NewBot(pref)
+	if err != nil {
+		return nil, err
+	}
+
+	mb := &managedBot{name: name, bot: b}
+	onError := b.onError
+	b.onError = func(err error, c Context) {
+		mb.record(err)
+		onError(err, c)
+	}
+
+	for _, f := range setup {
+		f(b)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.bots[name]; ok {
+		return nil, fmt.Errorf("telebot: bot %s is already registered", name)
+	}
+	if m.bots == nil {
+		m.bots = make(map[string]*managedBot)
+	}
+	m.bots[name] = mb
+
+	if m.started {
+		mb.start()
+	}
+	return b, nil
+}
+
+// Remove stops and unregisters the bot with the given name.
+// It reports whether the bot was registered.
+func (m *Manager) Remove(name string) bool {
+	m.mu.Lock()
+	mb, ok := m.bots[name]
+	delete(m.bots, name)
+	m.mu.Unlock()
+
+	if ok {
+		mb.stop()
+	}
+	return ok
+}
+
+// Bot returns the bot registered under the given name.
+func (m *Manager) Bot(name string) (*Bot, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mb, ok := m.bots[name]
+	if !ok {
+		return nil, false
+	}
+	return mb.bot, true
+}
+
+// Names returns the sorted names of the registered bots.
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.bots))
+	for name := range m.bots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start starts all the registered bots, each in its own goroutine.
+// Unlike Bot.Start, it doesn't block.
+func (m *Manager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.started = true
+	for _, mb := range m.bots {
+		mb.start()
+	}
+}
+
+// Stop stops all the bots and waits for them to finish.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	m.started = false
+	bots := make([]*managedBot, 0, len(m.bots))
+	for _, mb := range m.bots {
+		bots = append(bots, mb)
+	}
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, mb := range bots {
+		wg.Add(1)
+		go func(mb *managedBot) {
+			defer wg.Done()
+			mb.stop()
+		}(mb)
+	}
+	wg.Wait()
+}
+
+// Health returns the state of the bots sorted by their names.
+func (m *Manager) Health() []BotHealth {
+	m.mu.Lock()
+	bots := make([]*managedBot, 0, len(m.bots))
+	for _, mb := range m.bots {
+		bots = append(bots, mb)
+	}
+	m.mu.Unlock()
+
+	health := make([]BotHealth, len(bots))
+	for i, mb := range bots {
+		health[i] = mb.health()
+	}
+
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].Name < health[j].Name
+	})
+	return health
+}
+
+// ServeHTTP writes the Health of the bots in JSON. The status is 200
+// if all the bots are running and 503 otherwise.
+func (m *Manager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	health := m.Health()
+
+	status := http.StatusOK
+	for _, h := range health {
+		if !h.Running {
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(health)
+}
+
+func (mb *managedBot) start() {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	if mb.running {
+		return
+	}
+	prev := mb.done
+	mb.running = true
+	mb.done = make(chan struct{})
+
+	go func(prev, done chan struct{}) {
+		defer close(done)
+		if prev != nil {
+			// the bot may be still stopping
+			<-prev
+		}
+		mb.bot.Start()
+
+		mb.mu.Lock()
+		if mb.done == done {
+			mb.running = false
+		}
+		mb.mu.Unlock()
+	}(prev, mb.done)
+}
+
+func (mb *managedBot) stop() {
+	mb.mu.Lock()
+	running, done := mb.running, mb.done
+	mb.running = false
+	mb.mu.Unlock()
+
+	if !running {
+		return
+	}
+	// Start may have not reached its loop yet,
+	// or have returned already
+	mb.bot.stopUnless(done)
+	<-done
+}
+
+func (mb *managedBot) record(err error) {
+	atomic.AddInt64(&mb.errors, 1)
+
+	mb.mu.Lock()
+	mb.lastError = err
+	mb.errorTime = time.Now()
+	mb.mu.Unlock()
+}
+
+func (mb *managedBot) health() BotHealth {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+
+	h := BotHealth{
+		Name:    mb.name,
+		Running: mb.running,
+		Errors:  atomic.LoadInt64(&mb.errors),
+	}
+	if mb.bot.Me != nil {
+		h.Username = mb.bot.Me.Username
+	}
+	if mb.lastError != nil {
+		h.LastError = mb.lastError.Error()
+		h.ErrorDate = mb.errorTime.Unix()
+	}
+	return h
+}
@@ -0,0 +1,194 @@
+package telebot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testManagerServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			name := strings.Split(r.URL.Path, "/")[1]
+			w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"username":"` + name + `"}}`))
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Millisecond):
+			}
+			w.Write([]byte(`{"ok":true,"result":[]}`))
+		default:
+			w.Write([]byte(`{"ok":true,"result":true}`))
+		}
+	}))
+}
+
+func TestManager(t *testing.T) {
+	srv := testManagerServer()
+	defer srv.Close()
+
+	m := &Manager{Limiter: NewRateLimiter(1000, 10)}
+	add := func(name string) *Bot {
+		b, err := m.Add(name, Settings{URL: srv.URL, Token: name})
+		require.NoError(t, err)
+		return b
+	}
+
+	a := add("a")
+	b := add("b")
+	assert.Equal(t, "bota", a.Me.Username)
+	assert.Same(t, m.Client, a.client)
+	assert.Same(t, a.client, b.client)
+	assert.Equal(t, m.Limiter, b.limiter)
+
+	_, err := m.Add("a", Settings{URL: srv.URL, Token: "a"})
+	assert.Error(t, err)
+
+	got, ok := m.Bot("b")
+	assert.True(t, ok)
+	assert.Same(t, b, got)
+
+	health := func() (int, []BotHealth) {
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		return rec.Code, m.Health()
+	}
+
+	code, h := health()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, h[0].Running)
+
+	m.Start()
+
+	code, h = health()
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, h, 2)
+	assert.Equal(t, "a", h[0].Name)
+	assert.Equal(t, "bota", h[0].Username)
+	assert.True(t, h[1].Running)
+
+	a.OnError(errors.New("oops"), nil)
+	_, h = health()
+	assert.Equal(t, int64(1), h[0].Errors)
+	assert.Equal(t, "oops", h[0].LastError)
+	assert.NotZero(t, h[0].ErrorDate)
+
+	add("c")
+	assert.True(t, m.Remove("b"))
+	assert.False(t, m.Remove("b"))
+	assert.Equal(t, []string{"a", "c"}, m.Names())
+
+	_, h = health()
+	assert.True(t, h[1].Running)
+
+	m.Stop()
+
+	code, h = health()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, h[0].Running)
+	assert.False(t, h[1].Running)
+
+	// restarting right after stopping
+	m.Start()
+	m.Stop()
+}
+
+func TestManagerStopRightAway(t *testing.T) {
+	srv := testManagerServer()
+	defer srv.Close()
+
+	m := &Manager{}
+	m.Start()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for _, name := range []string{"a", "b", "c"} {
+			if _, err := m.Add(name, Settings{URL: srv.URL, Token: name}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		m.Remove("a")
+		m.Stop()
+
+		// the bot stopped on its own
+		m.Start()
+		b, _ := m.Bot("b")
+		b.Stop()
+		m.Stop()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("manager hangs on stop")
+	}
+	assert.False(t, m.Health()[0].Running)
+}
+
+func TestManagerSetup(t *testing.T) {
+	var polled int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true}}`))
+		case strings.HasSuffix(r.URL.Path, "/getUpdates") && atomic.AddInt32(&polled, 1) == 1:
+			// the very first update isn't lost
+			w.Write([]byte(`{"ok":true,"result":[{"update_id":1,"callback_query":{"id":"1"}}]}`))
+		default:
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`{"ok":true,"result":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	m := &Manager{}
+	m.Start()
+	defer m.Stop()
+
+	handled := make(chan string, 1)
+	_, err := m.Add("a", Settings{URL: srv.URL, Token: "a"}, func(b *Bot) {
+		b.Handle(OnCallback, func(c Context) error {
+			handled <- c.Callback().ID
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	select {
+	case id := <-handled:
+		assert.Equal(t, "1", id)
+	case <-time.After(time.Second):
+		t.Fatal("update isn't handled")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	require.NoError(t, l.Wait(ctx))
+	require.NoError(t, l.Wait(ctx))
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Millisecond))
+
+	require.NoError(t, l.Wait(ctx))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(15*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, l.Wait(ctx), context.Canceled)
+
+	assert.NoError(t, NewRateLimiter(0, 0).Wait(ctx))
+}
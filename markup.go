@@ -240,6 +240,25 @@ func (pt PollType) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// UnmarshalJSON implements json.Unmarshaler. It accepts both the plain
+// poll type and the object PollType is marshaled to.
+func (pt *PollType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*pt = PollType(s)
+		return nil
+	}
+
+	var aux struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*pt = PollType(aux.Type)
+	return nil
+}
+
 // ReplyRecipient combines both KeyboardButtonRequestUser
 // and KeyboardButtonRequestChat objects. Use inside ReplyButton
 // to request the user or chat sharing with respective settings.
@@ -161,7 +161,7 @@ func (a *Audio) MediaType() string {
 }
 
 func (a *Audio) MediaFile() *File {
-	a.fileName = a.FileName
+	a.setFileMeta(a.FileName, a.MIME)
 	return &a.File
 }
 
@@ -194,7 +194,7 @@ func (d *Document) MediaType() string {
 }
 
 func (d *Document) MediaFile() *File {
-	d.fileName = d.FileName
+	d.setFileMeta(d.FileName, d.MIME)
 	return &d.File
 }
 
@@ -235,7 +235,7 @@ func (v *Video) MediaType() string {
 }
 
 func (v *Video) MediaFile() *File {
-	v.fileName = v.FileName
+	v.setFileMeta(v.FileName, v.MIME)
 	return &v.File
 }
 
@@ -285,7 +285,7 @@ func (a *Animation) MediaType() string {
 }
 
 func (a *Animation) MediaFile() *File {
-	a.fileName = a.FileName
+	a.setFileMeta(a.FileName, a.MIME)
 	return &a.File
 }
 
@@ -317,6 +317,7 @@ func (v *Voice) MediaType() string {
 }
 
 func (v *Voice) MediaFile() *File {
+	v.setFileMeta("", v.MIME)
 	return &v.File
 }
 
@@ -0,0 +1,75 @@
+package telebot
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Metrics receives the events of the bot. It's set by Settings.Metrics,
+// see NewPrometheusMetrics for the built-in implementation.
+// The methods are called concurrently.
+type Metrics interface {
+	// UpdateReceived is called for every update processed by the bot.
+	// The kind is the name of the update field, e.g. "message".
+	UpdateReceived(kind string)
+
+	// UpdateDropped is called for every update dropped before reaching
+	// the handlers. The reason is "duplicate" for the updates dropped
+	// by Settings.Dedup.
+	UpdateDropped(reason string)
+
+	// HandlerDone is called after the handler of the endpoint is finished.
+	// The endpoint is the one passed to Handle, e.g. "/start" or OnText.
+	HandlerDone(endpoint string, latency time.Duration, err error)
+
+	// APIRequest is called after every Bot API request. The status is
+	// the HTTP status code, or 0 if no response was received.
+	APIRequest(method string, status int, latency time.Duration)
+
+	// FloodWait is called when the Bot API asks to repeat
+	// the request after the given delay.
+	FloodWait(method string, retryAfter time.Duration)
+
+	// QueueLength reports the number of updates waiting
+	// in the Updates channel.
+	QueueLength(n int)
+
+	// WebhookRejected is called when the webhook rejects a request,
+	// the reason is one of the WebhookStats fields in snake case.
+	WebhookRejected(reason string)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) UpdateReceived(string)                    {}
+func (nopMetrics) UpdateDropped(string)                     {}
+func (nopMetrics) HandlerDone(string, time.Duration, error) {}
+func (nopMetrics) APIRequest(string, int, time.Duration)    {}
+func (nopMetrics) FloodWait(string, time.Duration)          {}
+func (nopMetrics) QueueLength(int)                          {}
+func (nopMetrics) WebhookRejected(string)                   {}
+
+// meter returns the metrics of the bot. The events
+// of the bot made without NewBot are discarded.
+func (b *Bot) meter() Metrics {
+	if b.metrics == nil {
+		return nopMetrics{}
+	}
+	return b.metrics
+}
+
+// updateKind returns the name of the field the update is carrying.
+func updateKind(u Update) string {
+	v := reflect.ValueOf(u)
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Type.Kind() != reflect.Ptr || v.Field(i).IsNil() {
+			continue
+		}
+		return strings.Split(f.Tag.Get("json"), ",")[0]
+	}
+	return "unknown"
+}
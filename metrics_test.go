@@ -0,0 +1,86 @@
+package telebot
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{
+			"ok": false,
+			"error_code": 429,
+			"description": "Too Many Requests: retry after 8",
+			"parameters": {"retry_after": 8}
+		}`))
+	}))
+	defer srv.Close()
+
+	m := NewPrometheusMetrics()
+	b, err := NewBot(Settings{
+		URL:         srv.URL,
+		Offline:     true,
+		Synchronous: true,
+		Metrics:     m,
+		Dedup:       NewMemoryDedup(10),
+		OnError:     func(error, Context) {},
+	})
+	require.NoError(t, err)
+
+	_, err = b.Raw("sendMessage", nil)
+	require.Error(t, err)
+
+	b.Handle(OnAny, func(c Context) error {
+		return errors.New("oops")
+	})
+	b.ProcessUpdate(Update{ID: 1, Message: &Message{Text: "hi"}})
+	b.ProcessUpdate(Update{ID: 2, Callback: &Callback{}})
+	b.ProcessUpdate(Update{ID: 2, Callback: &Callback{}})
+
+	h := &Webhook{}
+	h.attach(b, make(chan Update))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	m.QueueLength(3)
+	m.HandlerDone("/start", 30*time.Millisecond, nil)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, line := range []string{
+		"# TYPE telebot_updates_total counter",
+		`telebot_updates_total{type="message"} 1`,
+		`telebot_updates_total{type="callback_query"} 1`,
+		`telebot_updates_dropped_total{reason="duplicate"} 1`,
+		`telebot_handler_errors_total{endpoint="any"} 1`,
+		`telebot_handler_duration_seconds_count{endpoint="any"} 1`,
+		`telebot_handler_duration_seconds_bucket{endpoint="/start",le="0.025"} 0`,
+		`telebot_handler_duration_seconds_bucket{endpoint="/start",le="0.05"} 1`,
+		`telebot_handler_duration_seconds_bucket{endpoint="/start",le="+Inf"} 1`,
+		`telebot_api_requests_total{method="sendMessage",status="429"} 1`,
+		`telebot_api_request_duration_seconds_count{method="sendMessage"} 1`,
+		`telebot_flood_waits_total{method="sendMessage"} 1`,
+		`telebot_flood_wait_seconds_total{method="sendMessage"} 8`,
+		"telebot_updates_queue_length 3",
+		`telebot_webhook_rejected_total{reason="bad_method"} 1`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+}
+
+func TestUpdateKind(t *testing.T) {
+	assert.Equal(t, "edited_message", updateKind(Update{EditedMessage: &Message{}}))
+	assert.Equal(t, "purchased_paid_media", updateKind(Update{PurchasedPaidMedia: &PaidMediaPurchased{}}))
+	assert.Equal(t, "unknown", updateKind(Update{ID: 1}))
+}
@@ -3,12 +3,14 @@ package middleware
 import (
 	"encoding/json"
 	"log"
+	"time"
 
 	tele "github.com/irijopa/telebot"
 )
 
 // Logger returns a middleware that logs incoming updates.
 // If no custom logger provided, log.Default() will be used.
+// See StructuredLogger to log them to the bot's tele.Logger.
 func Logger(logger ...*log.Logger) tele.MiddlewareFunc {
 	var l *log.Logger
 	if len(logger) > 0 {
@@ -25,3 +27,44 @@ func Logger(logger ...*log.Logger) tele.MiddlewareFunc {
 		}
 	}
 }
+
+// StructuredLogger returns a middleware that logs handled updates
+// to the bot's tele.Logger, along with the update and chat IDs, the
+// update kind, the handling latency and error. The content of updates
+// is logged only if payload is set, at the debug level and with
+// the fields of tele.Settings.Redact hidden. The bots other than
+// *tele.Bot are passed through.
+func StructuredLogger(payload ...bool) tele.MiddlewareFunc {
+	withPayload := len(payload) > 0 && payload[0]
+
+	return func(next tele.HandlerFunc) tele.HandlerFunc {
+		return func(c tele.Context) error {
+			b, ok := c.Bot().(*tele.Bot)
+			if !ok {
+				return next(c)
+			}
+
+			start := time.Now()
+			err := next(c)
+
+			u := c.Update()
+			fields := []interface{}{"update_id", u.ID, "kind", u.Kind()}
+			if chat := c.Chat(); chat != nil {
+				fields = append(fields, "chat_id", chat.ID)
+			}
+			fields = append(fields, "latency", time.Since(start))
+			if err != nil {
+				fields = append(fields, "error", err)
+			}
+
+			b.Logger().Log(tele.LevelInfo, "handled update", fields...)
+
+			if withPayload {
+				data, _ := json.Marshal(u)
+				b.Logger().Log(tele.LevelDebug, "update payload",
+					"update_id", u.ID, "update", string(b.Redact(data)))
+			}
+			return err
+		}
+	}
+}
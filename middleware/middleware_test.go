@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,3 +29,40 @@ func TestRecover(t *testing.T) {
 		Recover(onError)(h)(nil)
 	})
 }
+
+func TestStructuredLogger(t *testing.T) {
+	type record struct {
+		level  tele.LogLevel
+		msg    string
+		fields []interface{}
+	}
+	var records []record
+
+	b, err := tele.NewBot(tele.Settings{
+		Offline: true,
+		Redact:  []string{"text"},
+		Logger: tele.LoggerFunc(func(l tele.LogLevel, m string, f ...interface{}) {
+			records = append(records, record{l, m, f})
+		}),
+	})
+	require.NoError(t, err)
+
+	c := b.NewContext(tele.Update{ID: 1, Message: &tele.Message{Text: "secret", Chat: &tele.Chat{ID: 2}}})
+	h := func(tele.Context) error { return nil }
+
+	// the content isn't logged by default
+	require.NoError(t, StructuredLogger()(h)(c))
+	require.Len(t, records, 1)
+	assert.Equal(t, tele.LevelInfo, records[0].level)
+	assert.Equal(t, "handled update", records[0].msg)
+	assert.Equal(t, []interface{}{"update_id", 1, "kind", "message", "chat_id", int64(2)}, records[0].fields[:6])
+	assert.NotContains(t, fmt.Sprint(records[0].fields...), "secret")
+
+	records = nil
+	require.NoError(t, StructuredLogger(true)(h)(c))
+	require.Len(t, records, 2)
+	assert.Equal(t, tele.LevelDebug, records[1].level)
+	assert.Equal(t, "update payload", records[1].msg)
+	assert.Contains(t, records[1].fields[3], tele.RedactedValue)
+	assert.NotContains(t, records[1].fields[3], "secret")
+}
@@ -1,6 +1,7 @@
 package telebot
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -16,6 +17,15 @@ func TestPoll(t *testing.T) {
 	opts := []PollOption{{Text: "Option 1"}, {Text: "Option 2"}}
 	p.AddOptions(opts[0].Text, opts[1].Text)
 	assert.Equal(t, opts, p.Options)
+
+	// the poll is decoded back after being marshaled
+	data, err := json.Marshal(&Poll{Type: PollQuiz})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, p))
+	assert.Equal(t, PollQuiz, p.Type)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"regular"}`), p))
+	assert.Equal(t, PollRegular, p.Type)
 }
 
 func TestPollSend(t *testing.T) {
@@ -27,7 +37,7 @@ func TestPollSend(t *testing.T) {
 	}
 
 	_, err := b.Send(user, &Poll{}) // empty poll
-	assert.Equal(t, ErrBadPollOptions, err)
+	assert.ErrorIs(t, err, ErrBadPollOptions)
 
 	poll := &Poll{
 		Type:          PollQuiz,
@@ -0,0 +1,165 @@
+package telebot
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProgressFunc is called as the file is being uploaded or downloaded.
+// Total is -1 if the size of the file is unknown until the transfer
+// is over, then the func is called once more with done equal to total.
+type ProgressFunc func(done, total int64)
+
+// progressReader reports the number of bytes read through it.
+type progressReader struct {
+	io.Reader
+	done, total int64
+	progress    ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.done += int64(n)
+		r.progress(r.done, r.total)
+	}
+	if err == io.EOF && r.total < 0 {
+		// the size is known by now, report the completion
+		r.total = r.done
+		r.progress(r.done, r.total)
+	}
+	return n, err
+}
+
+// progressReadCloser is a progressReader closing the underlying reader.
+type progressReadCloser struct {
+	progressReader
+	io.Closer
+}
+
+func withProgress(rc io.ReadCloser, total int64, progress ProgressFunc) io.ReadCloser {
+	if progress == nil {
+		return rc
+	}
+	return &progressReadCloser{
+		progressReader: progressReader{
+			Reader:   rc,
+			total:    total,
+			progress: progress,
+		},
+		Closer: rc,
+	}
+}
+
+// fileSize figures out the size of the file being transferred.
+func fileSize(f File, r io.Reader) int64 {
+	if f.FileSize > 0 {
+		return f.FileSize
+	}
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		if fi, err := v.Stat(); err == nil {
+			return fi.Size()
+		}
+	}
+	return -1
+}
+
+// DefaultProgressFormat formats the progress as a percentage,
+// or as the number of megabytes if the total is unknown.
+func DefaultProgressFormat(done, total int64) string {
+	if total <= 0 {
+		return fmt.Sprintf("%.1f MB", float64(done)/(1<<20))
+	}
+	return fmt.Sprintf("%d%%", done*100/total)
+}
+
+// EditProgress returns a ProgressFunc that edits the text of the message
+// with the current progress, at most once per the given interval.
+// The final state is always reflected. If format is nil,
+// DefaultProgressFormat is used.
+//
+// Example:
+//
+//	status, _ := b.Send(chat, "Uploading...")
+//	progress := b.EditProgress(status, time.Second, nil)
+//	doc := &tele.Document{File: tele.FromDisk("report.pdf").WithProgress(progress)}
+//	b.Send(chat, doc)
+func (b *Bot) EditProgress(msg Editable, every time.Duration, format func(done, total int64) string) ProgressFunc {
+	return b.throttleProgress(every, format, func(text string) error {
+		_, err := b.Edit(msg, text)
+		return err
+	})
+}
+
+// EditCaptionProgress is the same as EditProgress,
+// but edits the caption of the message.
+func (b *Bot) EditCaptionProgress(msg Editable, every time.Duration, format func(done, total int64) string) ProgressFunc {
+	return b.throttleProgress(every, format, func(text string) error {
+		_, err := b.EditCaption(msg, text)
+		return err
+	})
+}
+
+func (b *Bot) throttleProgress(every time.Duration, format func(done, total int64) string, edit func(string) error) ProgressFunc {
+	if format == nil {
+		format = DefaultProgressFormat
+	}
+
+	var (
+		mu       sync.Mutex
+		last     time.Time
+		lastText string
+		busy     bool
+		pending  sync.WaitGroup
+	)
+
+	apply := func(text string) {
+		if err := edit(text); err != nil && !IsNotModified(err) {
+			b.debug(err)
+		}
+	}
+
+	return func(done, total int64) {
+		text := format(done, total)
+		final := total >= 0 && done >= total
+
+		mu.Lock()
+		if text == lastText || (!final && (busy || time.Since(last) < every)) {
+			mu.Unlock()
+			return
+		}
+		last, lastText, busy = time.Now(), text, true
+		if !final {
+			pending.Add(1)
+		}
+		mu.Unlock()
+
+		release := func() {
+			mu.Lock()
+			busy = false
+			mu.Unlock()
+		}
+
+		if final {
+			// the transfer is done, let the caller see the final state,
+			// which must not be overwritten by the edit in flight
+			pending.Wait()
+			apply(text)
+			release()
+			return
+		}
+
+		// don't stall the transfer while editing
+		go func() {
+			defer pending.Done()
+			apply(text)
+			release()
+		}()
+	}
+}
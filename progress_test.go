@@ -0,0 +1,78 @@
+package telebot
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 100<<10)
+
+	var (
+		mu    sync.Mutex
+		edits []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/sendDocument"):
+			io.Copy(io.Discard, r.Body)
+			w.Write([]byte(`{"ok":true,"result":{"document":{"file_id":"1"}}}`))
+		case strings.HasSuffix(r.URL.Path, "/editMessageText"):
+			var params map[string]string
+			json.NewDecoder(r.Body).Decode(&params)
+			mu.Lock()
+			edits = append(edits, params["text"])
+			mu.Unlock()
+			w.Write([]byte(`{"ok":true,"result":true}`))
+		case strings.HasSuffix(r.URL.Path, "/getFile"):
+			w.Write([]byte(`{"ok":true,"result":{"file_id":"1","file_path":"documents/1"}}`))
+		case strings.HasSuffix(r.URL.Path, "/documents/1"):
+			w.Write(content)
+		}
+	}))
+	defer srv.Close()
+
+	b, err := NewBot(Settings{URL: srv.URL, Offline: true})
+	require.NoError(t, err)
+
+	var done, total int64
+	doc := &Document{File: FromReader(bytes.NewReader(content)).WithProgress(func(d, t int64) {
+		done, total = d, t
+	})}
+
+	_, err = b.Send(&Chat{ID: 1}, doc)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), done)
+	assert.Equal(t, int64(len(content)), total)
+
+	status := &Message{ID: 1, Chat: &Chat{ID: 1}}
+	file := File{FileID: "1"}.WithProgress(b.EditProgress(status, time.Hour, nil))
+	assert.Contains(t, map[File]bool{file: true}, file, "File must stay comparable")
+
+	r, err := b.File(&file)
+	require.NoError(t, err)
+	_, err = io.Copy(io.Discard, r)
+	require.NoError(t, err)
+	r.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, edits)
+	assert.Equal(t, "100%", edits[len(edits)-1])
+}
+
+func TestDefaultProgressFormat(t *testing.T) {
+	assert.Equal(t, "50%", DefaultProgressFormat(1, 2))
+	assert.Equal(t, "1.5 MB", DefaultProgressFormat(3<<19, -1))
+}
@@ -0,0 +1,252 @@
+package telebot
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the histogram buckets of PrometheusMetrics in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PrometheusMetrics collects the bot metrics in memory and serves
+// them in the Prometheus text exposition format:
+//
+//	m := tele.NewPrometheusMetrics()
+//	b, err := tele.NewBot(tele.Settings{Metrics: m, ...})
+//	http.Handle("/metrics", m)
+//
+// The following metrics are exposed:
+//
+//	telebot_updates_total{type}                  counter
+//	telebot_updates_dropped_total{reason}        counter
+//	telebot_handler_duration_seconds{endpoint}   histogram
+//	telebot_handler_errors_total{endpoint}       counter
+//	telebot_api_requests_total{method,status}    counter
+//	telebot_api_request_duration_seconds{method} histogram
+//	telebot_flood_waits_total{method}            counter
+//	telebot_flood_wait_seconds_total{method}     counter
+//	telebot_updates_queue_length                 gauge
+//	telebot_webhook_rejected_total{reason}       counter
+type PrometheusMetrics struct {
+	updates         *promVec
+	updatesDropped  *promVec
+	handlerDuration *promVec
+	handlerErrors   *promVec
+	apiRequests     *promVec
+	apiDuration     *promVec
+	floodWaits      *promVec
+	floodWaitTime   *promVec
+	queueLength     *promVec
+	webhookRejected *promVec
+}
+
+// NewPrometheusMetrics returns the metrics with DefaultBuckets.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		updates: newPromVec("telebot_updates_total", "counter",
+			"Updates received by type.", nil, "type"),
+		updatesDropped: newPromVec("telebot_updates_dropped_total", "counter",
+			"Updates dropped before reaching the handlers by reason.", nil, "reason"),
+		handlerDuration: newPromVec("telebot_handler_duration_seconds", "histogram",
+			"Handler latency by endpoint.", DefaultBuckets, "endpoint"),
+		handlerErrors: newPromVec("telebot_handler_errors_total", "counter",
+			"Handler errors by endpoint.", nil, "endpoint"),
+		apiRequests: newPromVec("telebot_api_requests_total", "counter",
+			"Bot API requests by method and HTTP status.", nil, "method", "status"),
+		apiDuration: newPromVec("telebot_api_request_duration_seconds", "histogram",
+			"Bot API request latency by method.", DefaultBuckets, "method"),
+		floodWaits: newPromVec("telebot_flood_waits_total", "counter",
+			"Flood wait errors by method.", nil, "method"),
+		floodWaitTime: newPromVec("telebot_flood_wait_seconds_total", "counter",
+			"Requested flood wait time by method.", nil, "method"),
+		queueLength: newPromVec("telebot_updates_queue_length", "gauge",
+			"Updates waiting in the Updates channel.", nil),
+		webhookRejected: newPromVec("telebot_webhook_rejected_total", "counter",
+			"Webhook requests rejected by reason.", nil, "reason"),
+	}
+}
+
+func (m *PrometheusMetrics) UpdateReceived(kind string) {
+	m.updates.add(1, kind)
+}
+
+func (m *PrometheusMetrics) UpdateDropped(reason string) {
+	m.updatesDropped.add(1, reason)
+}
+
+func (m *PrometheusMetrics) HandlerDone(endpoint string, latency time.Duration, err error) {
+	endpoint = strings.TrimLeft(endpoint, "\a\f")
+	m.handlerDuration.observe(latency.Seconds(), endpoint)
+	if err != nil {
+		m.handlerErrors.add(1, endpoint)
+	}
+}
+
+func (m *PrometheusMetrics) APIRequest(method string, status int, latency time.Duration) {
+	code := "error"
+	if status > 0 {
+		code = strconv.Itoa(status)
+	}
+	m.apiRequests.add(1, method, code)
+	m.apiDuration.observe(latency.Seconds(), method)
+}
+
+func (m *PrometheusMetrics) FloodWait(method string, retryAfter time.Duration) {
+	m.floodWaits.add(1, method)
+	m.floodWaitTime.add(retryAfter.Seconds(), method)
+}
+
+func (m *PrometheusMetrics) QueueLength(n int) {
+	m.queueLength.set(float64(n))
+}
+
+func (m *PrometheusMetrics) WebhookRejected(reason string) {
+	m.webhookRejected.add(1, reason)
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	bw := bufio.NewWriter(w)
+	for _, v := range []*promVec{
+		m.updates,
+		m.updatesDropped,
+		m.handlerDuration,
+		m.handlerErrors,
+		m.apiRequests,
+		m.apiDuration,
+		m.floodWaits,
+		m.floodWaitTime,
+		m.queueLength,
+		m.webhookRejected,
+	} {
+		v.write(bw)
+	}
+	bw.Flush()
+}
+
+// promVec is a metric with a set of labels. Histograms
+// have buckets, counters and gauges have a single value.
+type promVec struct {
+	name, kind, help string
+	labels           []string
+	buckets          []float64
+
+	mu     sync.Mutex
+	series map[string]*promSeries
+}
+
+type promSeries struct {
+	values []string
+	sum    float64
+	count  uint64
+	counts []uint64 // per bucket, not cumulative
+}
+
+func newPromVec(name, kind, help string, buckets []float64, labels ...string) *promVec {
+	return &promVec{
+		name:    name,
+		kind:    kind,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*promSeries),
+	}
+}
+
+// get returns the series of the label values, v.mu must be held.
+func (v *promVec) get(values []string) *promSeries {
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &promSeries{values: values, counts: make([]uint64, len(v.buckets))}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *promVec) add(delta float64, values ...string) {
+	v.mu.Lock()
+	v.get(values).sum += delta
+	v.mu.Unlock()
+}
+
+func (v *promVec) set(value float64, values ...string) {
+	v.mu.Lock()
+	v.get(values).sum = value
+	v.mu.Unlock()
+}
+
+func (v *promVec) observe(value float64, values ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	s := v.get(values)
+	s.sum += value
+	s.count++
+	for i, le := range v.buckets {
+		if value <= le {
+			s.counts[i]++
+			break
+		}
+	}
+}
+
+func (v *promVec) write(w *bufio.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", v.name, v.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.name, v.kind)
+
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := v.series[k]
+		if v.kind != "histogram" {
+			fmt.Fprintf(w, "%s%s %s\n", v.name, v.labelPairs(s.values, ""), formatFloat(s.sum))
+			continue
+		}
+
+		var cumulative uint64
+		for i, le := range v.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, v.labelPairs(s.values, formatFloat(le)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, v.labelPairs(s.values, "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", v.name, v.labelPairs(s.values, ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", v.name, v.labelPairs(s.values, ""), s.count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelPairs formats the labels, le is added for the histogram buckets.
+func (v *promVec) labelPairs(values []string, le string) string {
+	var pairs []string
+	for i, name := range v.labels {
+		pairs = append(pairs, name+`="`+labelEscaper.Replace(values[i])+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
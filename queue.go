@@ -0,0 +1,513 @@
+package telebot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Queue is a buffer of updates shared between the process receiving
+// updates and the worker processes handling them.
+//
+// Telebot ships MemoryQueue and FileQueue, other backends can be
+// plugged in by implementing this interface.
+type Queue interface {
+	// Publish appends the update to the queue.
+	Publish(u Update) error
+
+	// Consume takes the next update from the queue, blocking until
+	// it's available. It returns nil update once stop is closed.
+	// Each update must be consumed exactly by one consumer.
+	Consume(stop chan struct{}) (*Update, error)
+}
+
+// Requeuer is implemented by the queues able to put consumed updates
+// back to the head of the queue. QueuePoller uses it to return the
+// updates it hasn't handed over to the bot. For the queues missing it,
+// such updates are published again, to the tail.
+type Requeuer interface {
+	// Requeue puts the updates to the head of the queue,
+	// so they are consumed next, in the given order.
+	Requeue(updates []Update) error
+}
+
+// QueuePublisher is a poller which publishes all the updates
+// received from the original poller into the queue instead of
+// handling them.
+//
+// Example:
+//
+//	// receiver
+//	b.Poller = &tele.QueuePublisher{Poller: webhook, Queue: queue}
+//
+//	// workers
+//	b.Poller = &tele.QueuePoller{Queue: queue}
+type QueuePublisher struct {
+	Poller Poller
+	Queue  Queue
+}
+
+// Poll publishes the updates of the original poller. Once stopped,
+// it publishes the updates the original poller has already received.
+func (p *QueuePublisher) Poll(b *Bot, dest chan Update, stop chan struct{}) {
+	middle := make(chan Update, cap(dest))
+	stopPoller := make(chan struct{})
+	stopConfirm := make(chan struct{})
+
+	go func() {
+		p.Poller.Poll(b, middle, stopPoller)
+		close(stopConfirm)
+	}()
+
+	publish := func(upd Update) {
+		if err := p.Queue.Publish(upd); err != nil {
+			b.OnError(err, nil)
+		}
+	}
+
+	for {
+		select {
+		case <-stop:
+			close(stopPoller)
+			for {
+				select {
+				case upd := <-middle:
+					// the original poller may still be sending
+					publish(upd)
+				case <-stopConfirm:
+					for {
+						select {
+						case upd := <-middle:
+							publish(upd)
+						default:
+							return
+						}
+					}
+				}
+			}
+		case upd := <-middle:
+			publish(upd)
+		}
+	}
+}
+
+// QueuePoller is a poller which consumes updates from the queue.
+//
+// An update is removed from the queue once it's consumed. When the poller
+// is stopped, the updates consumed but not taken by the bot yet, i.e. the
+// one being handed over and the ones left in the updates channel,
+// are returned to the queue, see Requeuer. The updates being handled
+// are not, so the ones of a crashed process are lost: the delivery
+// is at most once.
+type QueuePoller struct {
+	Queue Queue
+}
+
+// Poll consumes the queue.
+func (p *QueuePoller) Poll(b *Bot, dest chan Update, stop chan struct{}) {
+	for {
+		upd, err := p.Queue.Consume(stop)
+		if err != nil {
+			b.OnError(err, nil)
+		}
+
+		if upd != nil {
+			select {
+			case dest <- *upd:
+				continue
+			case <-stop:
+				p.requeue(b, dest, upd)
+				return
+			}
+		}
+
+		select {
+		case <-stop:
+			p.requeue(b, dest, nil)
+			return
+		default:
+		}
+
+		if err != nil {
+			// don't spin on persistent errors
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// requeue returns the updates left in dest, followed by the pending one,
+// to the queue. It relies on dest not being read once stop is closed.
+func (p *QueuePoller) requeue(b *Bot, dest chan Update, pending *Update) {
+	var updates []Update
+	for len(dest) > 0 {
+		updates = append(updates, <-dest)
+	}
+	if pending != nil {
+		updates = append(updates, *pending)
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	if q, ok := p.Queue.(Requeuer); ok {
+		if err := q.Requeue(updates); err != nil {
+			b.OnError(err, nil)
+		}
+		return
+	}
+	for _, u := range updates {
+		if err := p.Queue.Publish(u); err != nil {
+			b.OnError(err, nil)
+		}
+	}
+}
+
+// MemoryQueue is a queue kept in memory. It can only be
+// shared between bots within the same process.
+type MemoryQueue struct {
+	updates chan Update
+
+	mu       sync.Mutex
+	returned []Update
+	requeued chan struct{}
+}
+
+// NewMemoryQueue creates a memory queue of the given capacity.
+func NewMemoryQueue(capacity int) *MemoryQueue {
+	return &MemoryQueue{
+		updates:  make(chan Update, capacity),
+		requeued: make(chan struct{}, 1),
+	}
+}
+
+// Publish appends the update, blocking while the queue is full.
+func (q *MemoryQueue) Publish(u Update) error {
+	q.updates <- u
+	return nil
+}
+
+// Consume takes the next update, the requeued ones go first.
+func (q *MemoryQueue) Consume(stop chan struct{}) (*Update, error) {
+	for {
+		q.mu.Lock()
+		if len(q.returned) > 0 {
+			u := q.returned[0]
+			q.returned = q.returned[1:]
+			q.mu.Unlock()
+			return &u, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case u := <-q.updates:
+			return &u, nil
+		case <-q.requeued:
+		case <-stop:
+			return nil, nil
+		}
+	}
+}
+
+// Requeue puts the updates to the head of the queue. Unlike Publish,
+// it never blocks, as the capacity isn't applied to them.
+func (q *MemoryQueue) Requeue(updates []Update) error {
+	q.mu.Lock()
+	q.returned = append(append([]Update(nil), updates...), q.returned...)
+	q.mu.Unlock()
+
+	select {
+	case q.requeued <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// FileQueue is a queue stored in the directory as a log of segment
+// files with JSON-encoded updates, one per line. It can be shared
+// between processes having access to the same directory.
+//
+// The access is serialized with a lock file, so both publishers and
+// consumers are safe to be run concurrently. Consumed segments are
+// removed once the next one is started.
+type FileQueue struct {
+	Dir string
+
+	// SegmentSize is the size after which a new segment
+	// is started, defaulted to 64 MB.
+	SegmentSize int64
+
+	// PollInterval is how often an empty queue is checked
+	// for new updates, defaulted to 100ms.
+	PollInterval time.Duration
+}
+
+const (
+	fileQueueLock   = "lock"
+	fileQueueOffset = "offset"
+	fileQueueExt    = ".log"
+
+	// fileQueueStaleLock is the age after which the lock file
+	// is considered to be left by a crashed process.
+	fileQueueStaleLock = 10 * time.Second
+)
+
+// NewFileQueue creates a file queue in the given directory.
+func NewFileQueue(dir string) (*FileQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, wrapError(err)
+	}
+	return &FileQueue{Dir: dir}, nil
+}
+
+// Publish appends the update to the last segment.
+func (q *FileQueue) Publish(u Update) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return wrapError(err)
+	}
+	data = append(data, '\n')
+
+	if err := q.lock(); err != nil {
+		return err
+	}
+	defer q.unlock()
+
+	segs, err := q.segments()
+	if err != nil {
+		return err
+	}
+
+	var seg int64
+	if len(segs) > 0 {
+		seg = segs[len(segs)-1]
+		if fi, err := os.Stat(q.segmentPath(seg)); err == nil && fi.Size() >= q.segmentSize() {
+			seg++
+		}
+	}
+
+	f, err := os.OpenFile(q.segmentPath(seg), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Consume takes the next update, polling the directory
+// with PollInterval while the queue is empty.
+func (q *FileQueue) Consume(stop chan struct{}) (*Update, error) {
+	interval := q.PollInterval
+	if interval <= 0 {
+		interval = 100 * time.Millisecond
+	}
+
+	for {
+		data, err := q.next()
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			var u Update
+			if err := json.Unmarshal(data, &u); err != nil {
+				return nil, wrapError(err)
+			}
+			return &u, nil
+		}
+
+		select {
+		case <-stop:
+			return nil, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// next reads the next line from the log and moves the offset.
+// Returns nil if there is nothing to read yet.
+func (q *FileQueue) next() ([]byte, error) {
+	if err := q.lock(); err != nil {
+		return nil, err
+	}
+	defer q.unlock()
+
+	seg, pos, err := q.offset()
+	if err != nil {
+		return nil, err
+	}
+
+	segs, err := q.segments()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var later bool
+		for _, s := range segs {
+			if s > seg {
+				later = true
+				break
+			}
+		}
+
+		line, err := readLine(q.segmentPath(seg), pos)
+		if err != nil {
+			return nil, err
+		}
+		if line != nil {
+			pos += int64(len(line))
+			if err := q.setOffset(seg, pos); err != nil {
+				return nil, err
+			}
+			return line, nil
+		}
+		if !later {
+			return nil, nil
+		}
+
+		// the segment is consumed, move on to the next one
+		os.Remove(q.segmentPath(seg))
+		for _, s := range segs {
+			if s > seg {
+				seg, pos = s, 0
+				break
+			}
+		}
+		if err := q.setOffset(seg, pos); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readLine reads a complete line starting at the given position.
+// Returns nil if the file doesn't exist or the line is incomplete.
+func readLine(path string, pos int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(pos, io.SeekStart); err != nil {
+		return nil, wrapError(err)
+	}
+
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return line, nil
+}
+
+func (q *FileQueue) segmentSize() int64 {
+	if q.SegmentSize <= 0 {
+		return 64 << 20
+	}
+	return q.SegmentSize
+}
+
+func (q *FileQueue) segmentPath(seg int64) string {
+	return filepath.Join(q.Dir, fmt.Sprintf("%020d%s", seg, fileQueueExt))
+}
+
+func (q *FileQueue) segments() ([]int64, error) {
+	files, err := ioutil.ReadDir(q.Dir)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	var segs []int64
+	for _, fi := range files {
+		name := fi.Name()
+		if !strings.HasSuffix(name, fileQueueExt) {
+			continue
+		}
+		seg, err := strconv.ParseInt(strings.TrimSuffix(name, fileQueueExt), 10, 64)
+		if err == nil {
+			segs = append(segs, seg)
+		}
+	}
+
+	sort.Slice(segs, func(i, j int) bool { return segs[i] < segs[j] })
+	return segs, nil
+}
+
+func (q *FileQueue) offset() (seg, pos int64, _ error) {
+	data, err := ioutil.ReadFile(filepath.Join(q.Dir, fileQueueOffset))
+	if os.IsNotExist(err) {
+		segs, err := q.segments()
+		if err != nil || len(segs) == 0 {
+			return 0, 0, err
+		}
+		return segs[0], 0, nil
+	}
+	if err != nil {
+		return 0, 0, wrapError(err)
+	}
+
+	if _, err := fmt.Sscan(string(bytes.TrimSpace(data)), &seg, &pos); err != nil {
+		return 0, 0, fmt.Errorf("telebot: malformed queue offset: %w", err)
+	}
+	return seg, pos, nil
+}
+
+func (q *FileQueue) setOffset(seg, pos int64) error {
+	path := filepath.Join(q.Dir, fileQueueOffset)
+	data := []byte(fmt.Sprintf("%d %d\n", seg, pos))
+
+	// write and rename, so the offset is never left half-written
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return wrapError(err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func (q *FileQueue) lock() error {
+	path := filepath.Join(q.Dir, fileQueueLock)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return nil
+		}
+		if !os.IsExist(err) {
+			return wrapError(err)
+		}
+
+		if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) > fileQueueStaleLock {
+			os.Remove(path)
+			continue
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (q *FileQueue) unlock() {
+	os.Remove(filepath.Join(q.Dir, fileQueueLock))
+}
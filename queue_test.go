@@ -0,0 +1,155 @@
+package telebot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileQueue(t *testing.T) {
+	q, err := NewFileQueue(t.TempDir())
+	require.NoError(t, err)
+	q.SegmentSize = 32 // a segment per update
+
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, q.Publish(Update{ID: i, Message: &Message{Text: "hello"}}))
+	}
+
+	segs, err := q.segments()
+	require.NoError(t, err)
+	assert.Len(t, segs, 5)
+
+	stop := make(chan struct{})
+	for i := 1; i <= 3; i++ {
+		u, err := q.Consume(stop)
+		require.NoError(t, err)
+		assert.Equal(t, i, u.ID)
+		assert.Equal(t, "hello", u.Message.Text)
+	}
+
+	// consumed segments are removed
+	segs, err = q.segments()
+	require.NoError(t, err)
+	assert.Len(t, segs, 3)
+
+	// another consumer continues from the same offset
+	q2 := &FileQueue{Dir: q.Dir}
+	u, err := q2.Consume(stop)
+	require.NoError(t, err)
+	assert.Equal(t, 4, u.ID)
+
+	close(stop)
+	u, err = q.Consume(stop)
+	require.NoError(t, err)
+	assert.Equal(t, 5, u.ID)
+
+	u, err = q.Consume(stop)
+	require.NoError(t, err)
+	assert.Nil(t, u)
+}
+
+func TestQueuePoller(t *testing.T) {
+	q := NewMemoryQueue(10)
+
+	tp := newTestPoller()
+	receiver, err := NewBot(Settings{Offline: true, Poller: &QueuePublisher{Poller: tp, Queue: q}})
+	require.NoError(t, err)
+
+	worker, err := NewBot(Settings{Offline: true, Poller: &QueuePoller{Queue: q}, Synchronous: true})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	var ids []int
+	worker.Handle(OnCallback, func(c Context) error {
+		ids = append(ids, c.Update().ID)
+		wg.Done()
+		return nil
+	})
+
+	go receiver.Start()
+	go worker.Start()
+
+	tp.updates <- Update{ID: 1, Callback: &Callback{}}
+	tp.updates <- Update{ID: 2, Callback: &Callback{}}
+
+	wg.Wait()
+	receiver.Stop()
+	worker.Stop()
+
+	assert.Equal(t, []int{1, 2}, ids)
+}
+
+func TestQueuePoller_Stop(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true})
+	require.NoError(t, err)
+
+	consume := func(q Queue) (ids []int) {
+		stop := make(chan struct{})
+		time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+		for {
+			u, err := q.Consume(stop)
+			require.NoError(t, err)
+			if u == nil {
+				return ids
+			}
+			ids = append(ids, u.ID)
+		}
+	}
+
+	poll := func(q Queue) {
+		// nobody reads the updates, as the bot is stopping
+		dest := make(chan Update, 1)
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			(&QueuePoller{Queue: q}).Poll(b, dest, stop)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return len(dest) == 1 }, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		close(stop)
+		<-done
+	}
+
+	mq := NewMemoryQueue(10)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, mq.Publish(Update{ID: i}))
+	}
+	poll(mq)
+	assert.Equal(t, []int{1, 2, 3}, consume(mq))
+
+	fq, err := NewFileQueue(t.TempDir())
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, fq.Publish(Update{ID: i}))
+	}
+	poll(fq)
+	assert.ElementsMatch(t, []int{1, 2, 3}, consume(fq))
+
+	t.Run("publisher", func(t *testing.T) {
+		q := NewMemoryQueue(10)
+		tp := newTestPoller()
+		tp.updates <- Update{ID: 1}
+
+		p := &QueuePublisher{Poller: blockingPoller{tp}, Queue: q}
+		stop := make(chan struct{})
+		close(stop)
+		p.Poll(b, make(chan Update, 1), stop)
+
+		assert.Equal(t, []int{1}, consume(q))
+	})
+}
+
+// blockingPoller hands the updates over before it sees stop.
+type blockingPoller struct{ *testPoller }
+
+func (p blockingPoller) Poll(b *Bot, updates chan Update, stop chan struct{}) {
+	updates <- <-p.updates
+	<-stop
+}
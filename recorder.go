@@ -0,0 +1,214 @@
+package telebot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Record is a single line of the updates recording.
+type Record struct {
+	Time   time.Time       `json:"time"`
+	Update json.RawMessage `json:"update"`
+}
+
+// Recorder writes every incoming update as a JSON line with
+// the time it was received. The updates are written as Telegram
+// sent them, including the fields telebot doesn't know about.
+// The recording can be played back later with ReplayPoller.
+//
+// Wrap the poller of the bot with it:
+//
+//	rec, err := tele.NewFileRecorder("updates.jsonl")
+//	if err != nil {
+//		return err
+//	}
+//	defer rec.Close()
+//
+//	b.Poller = rec.Poller(poller)
+type Recorder struct {
+	mu sync.Mutex
+	w  io.Writer
+
+	// OnError is called if the update can't be recorded.
+	OnError func(error)
+}
+
+// NewRecorder creates a recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// NewFileRecorder creates a recorder appending to the file.
+func NewFileRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return NewRecorder(f), nil
+}
+
+// Record writes the update. The updates received from Telegram
+// are written exactly as they were sent, the other ones are encoded.
+func (r *Recorder) Record(u Update) error {
+	data := json.RawMessage(u.raw)
+	if u.raw == "" {
+		var err error
+		if data, err = json.Marshal(u); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	// don't let the encoder escape HTML in the original JSON
+	var line bytes.Buffer
+	enc := json.NewEncoder(&line)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(Record{Time: time.Now(), Update: data}); err != nil {
+		return wrapError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.w.Write(line.Bytes()); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+// Poller wraps the poller, so the updates are recorded before
+// they are processed. The bot keeps the original JSON of the
+// updates only while it's polled by such a poller.
+func (r *Recorder) Poller(p Poller) Poller {
+	return &recordingPoller{poller: NewMiddlewarePoller(p, r.Filter)}
+}
+
+type recordingPoller struct {
+	poller Poller
+}
+
+func (p *recordingPoller) Poll(b *Bot, dest chan Update, stop chan struct{}) {
+	atomic.StoreInt32(&b.recording, 1)
+	defer atomic.StoreInt32(&b.recording, 0)
+
+	p.poller.Poll(b, dest, stop)
+}
+
+// decodeUpdate decodes the update received by the bot,
+// keeping its original JSON if the updates are recorded.
+func (b *Bot) decodeUpdate(data []byte, u *Update) error {
+	if err := json.Unmarshal(data, u); err != nil {
+		return err
+	}
+	if atomic.LoadInt32(&b.recording) == 1 {
+		u.raw = string(data)
+	}
+	return nil
+}
+
+// Filter records the update and lets it through. It's meant
+// to be used within the MiddlewarePoller, see Recorder.Poller.
+func (r *Recorder) Filter(u *Update) bool {
+	if err := r.Record(*u); err != nil && r.OnError != nil {
+		r.OnError(err)
+	}
+	return true
+}
+
+// Close closes the underlying writer if it's an io.Closer.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// ReplayPoller plays back the recording made by Recorder.
+//
+// Example:
+//
+//	f, _ := os.Open("updates.jsonl")
+//	b.Poller = &tele.ReplayPoller{Reader: f, Speed: 1}
+type ReplayPoller struct {
+	Reader io.Reader
+
+	// Speed is the playback rate relative to the recording:
+	// 1 is real time, 2 is twice as fast and so on.
+	// Zero means no delays between updates at all.
+	Speed float64
+
+	// Step, if set, makes the poller wait for a value
+	// from the channel before sending each update.
+	Step chan struct{}
+
+	// Done, if set, gets closed once the recording is over.
+	Done chan struct{}
+}
+
+// maxRecordSize limits the size of a single recorded line.
+const maxRecordSize = 64 << 20
+
+// Poll replays the recording.
+func (p *ReplayPoller) Poll(b *Bot, dest chan Update, stop chan struct{}) {
+	scanner := bufio.NewScanner(p.Reader)
+	scanner.Buffer(nil, maxRecordSize)
+
+	var last time.Time
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			b.OnError(fmt.Errorf("telebot: malformed record: %w", err), nil)
+			continue
+		}
+
+		var u Update
+		if err := b.decodeUpdate(rec.Update, &u); err != nil {
+			b.OnError(fmt.Errorf("telebot: malformed recorded update: %w", err), nil)
+			continue
+		}
+
+		if p.Speed > 0 && !last.IsZero() {
+			delay := time.Duration(float64(rec.Time.Sub(last)) / p.Speed)
+			if delay > 0 {
+				select {
+				case <-stop:
+					return
+				case <-time.After(delay):
+				}
+			}
+		}
+		last = rec.Time
+
+		if p.Step != nil {
+			select {
+			case <-stop:
+				return
+			case <-p.Step:
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case dest <- u:
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		b.OnError(wrapError(err), nil)
+	}
+	if p.Done != nil {
+		close(p.Done)
+	}
+
+	<-stop
+}
@@ -0,0 +1,99 @@
+package telebot
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+
+	tp := newTestPoller()
+	b, err := NewBot(Settings{Offline: true, Poller: rec.Poller(tp)})
+	require.NoError(t, err)
+
+	processed := make(chan int, 2)
+	b.Handle(OnCallback, func(c Context) error {
+		processed <- c.Update().ID
+		return nil
+	})
+
+	go b.Start()
+	tp.updates <- Update{ID: 1, Callback: &Callback{Data: "first"}}
+	tp.updates <- Update{ID: 2, Callback: &Callback{Data: "second"}}
+	<-processed
+	<-processed
+	b.Stop()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	replay := &ReplayPoller{
+		Reader: strings.NewReader(buf.String()),
+		Speed:  1000,
+		Step:   make(chan struct{}),
+		Done:   make(chan struct{}),
+	}
+
+	b, err = NewBot(Settings{Offline: true, Synchronous: true, Poller: replay})
+	require.NoError(t, err)
+
+	data := make(chan string, 2)
+	b.Handle(OnCallback, func(c Context) error {
+		data <- c.Callback().Data
+		return nil
+	})
+
+	go b.Start()
+	replay.Step <- struct{}{}
+	assert.Equal(t, "first", <-data)
+	replay.Step <- struct{}{}
+	assert.Equal(t, "second", <-data)
+
+	select {
+	case <-replay.Done:
+	case <-time.After(time.Second):
+		t.Fatal("replay is not finished")
+	}
+	b.Stop()
+}
+
+func TestRecorder_Raw(t *testing.T) {
+	const raw = `{"update_id":1,"message":{"message_id":2,"text":"<b>hi</b>","future_field":{"a":1}}}`
+
+	var b Bot
+
+	// the JSON is kept only while recording
+	var u Update
+	require.NoError(t, b.decodeUpdate([]byte(raw), &u))
+	assert.Empty(t, u.raw)
+
+	b.recording = 1
+	require.NoError(t, b.decodeUpdate([]byte(raw), &u))
+
+	var buf bytes.Buffer
+	require.NoError(t, NewRecorder(&buf).Record(u))
+	assert.Contains(t, buf.String(), `"update":`+raw+`}`)
+
+	// the replayed updates are recorded the same way
+	var rec Record
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec))
+	var replayed Update
+	require.NoError(t, b.decodeUpdate(rec.Update, &replayed))
+
+	var again bytes.Buffer
+	require.NoError(t, NewRecorder(&again).Record(replayed))
+	assert.Contains(t, again.String(), `"update":`+raw+`}`)
+
+	// the updates not coming from Telegram are encoded
+	buf.Reset()
+	require.NoError(t, NewRecorder(&buf).Record(Update{ID: 3}))
+	assert.Contains(t, buf.String(), `"update_id":3`)
+}
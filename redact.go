@@ -0,0 +1,132 @@
+package telebot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the bot token and the redacted fields
+// in errors and Verbose logs.
+const RedactedValue = "<redacted>"
+
+// secretFields are always redacted in Verbose logs.
+var secretFields = []string{"secret_token", "provider_token"}
+
+// tokenRx matches the tokens issued by @BotFather.
+var tokenRx = regexp.MustCompile(`\d+:[\w-]{30,}`)
+
+// redactToken hides the bot token anywhere in s.
+func redactToken(s, token string) string {
+	if token != "" {
+		s = strings.ReplaceAll(s, token, RedactedValue)
+	}
+	return tokenRx.ReplaceAllString(s, RedactedValue)
+}
+
+// Redact returns the JSON with the values of the Settings.Redact
+// fields and the bot token hidden, as they are in Verbose logs.
+func (b *Bot) Redact(data []byte) []byte {
+	data = redactJSON(data, b.redact)
+	return []byte(redactToken(string(data), b.Token))
+}
+
+// redactError hides the bot token in the URL of the failed request
+// and in the body of the unexpected response, which proxies often
+// echo the URL in. Only the tokens of @BotFather format are hidden
+// if the token is empty.
+func redactError(err error, token string) {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactToken(urlErr.URL, token)
+	}
+
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		transportErr.Body = redactToken(transportErr.Body, token)
+	}
+}
+
+// redactFields returns the set of fields hidden in Verbose logs.
+func redactFields(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields)+len(secretFields))
+	for _, f := range secretFields {
+		set[f] = true
+	}
+	for _, f := range fields {
+		set[f] = true
+	}
+	return set
+}
+
+// redactValue replaces the values of the given fields in v,
+// which is decoded JSON. Strings holding JSON objects or arrays,
+// e.g. the serialized parameters, are redacted as well.
+func redactValue(v interface{}, fields map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if fields[k] {
+				v[k] = RedactedValue
+			} else {
+				v[k] = redactValue(val, fields)
+			}
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactValue(val, fields)
+		}
+	case string:
+		if !strings.HasPrefix(v, "{") && !strings.HasPrefix(v, "[") {
+			return v
+		}
+		nested, err := unmarshalRedacted([]byte(v))
+		if err != nil {
+			return v
+		}
+		data, err := marshalRedacted(redactValue(nested, fields))
+		if err != nil {
+			return v
+		}
+		return string(data)
+	}
+	return v
+}
+
+// redactJSON returns data with the given fields redacted.
+// Invalid JSON is returned unchanged.
+func redactJSON(data []byte, fields map[string]bool) []byte {
+	v, err := unmarshalRedacted(data)
+	if err != nil {
+		return data
+	}
+	redacted, err := marshalRedacted(redactValue(v, fields))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+// unmarshalRedacted decodes data keeping the numbers as is.
+func unmarshalRedacted(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	err := dec.Decode(&v)
+	return v, err
+}
+
+// marshalRedacted encodes v without escaping the brackets of RedactedValue.
+func marshalRedacted(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
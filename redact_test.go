@@ -0,0 +1,84 @@
+package telebot
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	const token = "123456:ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghi"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":{"contact":{"phone_number":"+100500","first_name":"Ann"}}}`))
+	}))
+
+	b, err := NewBot(Settings{
+		URL:     srv.URL,
+		Token:   token,
+		Offline: true,
+		Verbose: true,
+		Redact:  []string{"phone_number"},
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	_, err = b.Raw("sendContact", map[string]string{
+		"chat_id":      "1",
+		"phone_number": "+100500",
+		"reply_markup": `{"secret_token":"s3cr3t"}`,
+	})
+	require.NoError(t, err)
+
+	logged := buf.String()
+	assert.Contains(t, logged, "sendContact")
+	assert.Contains(t, logged, "Ann")
+	assert.Contains(t, logged, RedactedValue)
+	assert.NotContains(t, logged, "+100500")
+	assert.NotContains(t, logged, "s3cr3t")
+
+	srv.Close()
+
+	_, err = b.Raw("getMe", nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), token)
+	assert.Contains(t, err.Error(), "/bot"+RedactedValue+"/getMe")
+
+	err = b.DownloadTo(&File{FilePath: "photos/file_1.jpg"}, &buf)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), token)
+
+	// the custom URL path looking like the token one
+	b.URL = srv.URL + "/botapi"
+	_, err = b.Raw("getMe", nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), token)
+	assert.Contains(t, err.Error(), "/botapi/bot"+RedactedValue+"/getMe")
+
+	// the error pages echoing the request URL
+	b.Token = "TEST"
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>502 Bad Gateway: " + r.URL.Path + "</html>"))
+	}))
+	defer srv.Close()
+
+	b.URL = srv.URL
+	_, err = b.Raw("getMe", nil)
+	var transportErr *TransportError
+	require.ErrorAs(t, err, &transportErr)
+	assert.Equal(t, "<html>502 Bad Gateway: /bot"+RedactedValue+"/getMe</html>", transportErr.Body)
+
+	assert.Equal(t, "got "+RedactedValue, redactToken("got "+token, ""))
+	assert.Equal(t, "a"+RedactedValue+"b", redactToken("aTESTb", "TEST"))
+	assert.Equal(t, `{"id":-100123456789012}`, string(redactJSON([]byte(`{"id":-100123456789012}`), nil)))
+}
@@ -204,6 +204,8 @@ func (a *Animation) Send(b *Bot, to Recipient, opt *SendOptions) (*Message, erro
 	// file_name is required, without it animation sends as a document
 	if params["file_name"] == "" && a.File.OnDisk() {
 		params["file_name"] = filepath.Base(a.File.FileLocal)
+	} else if params["file_name"] == "" && a.File.fileName != "" {
+		params["file_name"] = a.File.fileName
 	}
 
 	msg, err := b.sendMedia(a, params, thumbnailToFilemap(a.Thumbnail))
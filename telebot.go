@@ -34,6 +34,8 @@ var (
 	ErrCouldNotUpdate  = errors.New("telebot: could not fetch new updates")
 	ErrTrueResult      = errors.New("telebot: result is True")
 	ErrBadContext      = errors.New("telebot: context does not contain message")
+	ErrFileTooLarge    = errors.New("telebot: file is too large to download")
+	ErrIncompleteFile  = errors.New("telebot: downloaded file is incomplete")
 )
 
 const DefaultApiURL = "https://api.telegram.org"
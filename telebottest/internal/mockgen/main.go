@@ -0,0 +1,276 @@
+// Command mockgen generates the mock of the API interface.
+//
+// Usage:
+//
+//	go run ./internal/mockgen -src ../api.go -out mock.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = `// Code generated by mockgen from api.go. DO NOT EDIT.
+
+package telebottest
+
+`
+
+func main() {
+	src := flag.String("src", "../api.go", "file declaring the API interface")
+	out := flag.String("out", "mock.go", "output file")
+	flag.Parse()
+
+	code, err := generate(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(src string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, src, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	iface := findInterface(f, "API")
+	if iface == nil {
+		return nil, fmt.Errorf("mockgen: API interface is not found in %s", src)
+	}
+
+	var imports []string
+	for _, spec := range f.Imports {
+		imports = append(imports, spec.Path.Value)
+	}
+
+	var methods []*ast.Field
+	for _, m := range iface.Methods.List {
+		if len(m.Names) > 0 {
+			methods = append(methods, m)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Names[0].Name < methods[j].Names[0].Name
+	})
+
+	var body bytes.Buffer
+	body.WriteString(`// MockAPI is a mock of tele.API for unit-testing handlers with a context
+// created by tele.NewContext. Every call is recorded, and the method calls
+// the function field of the same name with the Func suffix, if it's set.
+// Otherwise, zero values are returned.
+//
+//	api := &telebottest.MockAPI{}
+//	api.SendFunc = func(to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error) {
+//		return &tele.Message{}, nil
+//	}
+//
+//	c := tele.NewContext(api, tele.Update{Message: msg})
+//	err := handler(c)
+//	calls := api.CallsOf("Send")
+type MockAPI struct {
+	mu    sync.Mutex
+	calls []Call
+
+`)
+	for _, m := range methods {
+		fmt.Fprintf(&body, "\t%sFunc %s\n", m.Names[0].Name, typeString(fset, qualify(m.Type)))
+	}
+	body.WriteString("}\n\n")
+
+	body.WriteString(`var _ tele.API = (*MockAPI)(nil)
+
+// Call is a recorded call of the MockAPI method.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Calls returns all the recorded calls.
+func (m *MockAPI) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsOf returns the recorded calls of the method.
+func (m *MockAPI) CallsOf(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+func (m *MockAPI) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	m.mu.Unlock()
+}
+`)
+
+	for _, m := range methods {
+		writeMethod(&body, fset, m.Names[0].Name, m.Type.(*ast.FuncType))
+	}
+
+	used := body.String()
+	var paths []string
+	for _, path := range imports {
+		name, _ := strconv.Unquote(path)
+		name = name[strings.LastIndex(name, "/")+1:]
+		if strings.Contains(used, name+".") {
+			paths = append(paths, path)
+		}
+	}
+	paths = append(paths, `"sync"`)
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	buf.WriteString("import (\n")
+	for _, p := range paths {
+		fmt.Fprintf(&buf, "\t%s\n", p)
+	}
+	buf.WriteString("\n\ttele \"github.com/irijopa/telebot\"\n)\n\n")
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+func writeMethod(w *bytes.Buffer, fset *token.FileSet, name string, fn *ast.FuncType) {
+	var (
+		params, args, callArgs []string
+		results, names         []string
+	)
+
+	var i int
+	for _, field := range fn.Params.List {
+		typ := typeString(fset, qualify(field.Type))
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for j := 0; j < n; j++ {
+			arg := "a" + strconv.Itoa(i)
+			if j < len(field.Names) && field.Names[j].Name != "m" {
+				arg = field.Names[j].Name
+			}
+			i++
+
+			params = append(params, arg+" "+typ)
+			args = append(args, arg)
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				callArgs = append(callArgs, arg+"...")
+			} else {
+				callArgs = append(callArgs, arg)
+			}
+		}
+	}
+
+	if fn.Results != nil {
+		for k, field := range fn.Results.List {
+			r := "r" + strconv.Itoa(k)
+			names = append(names, r)
+			results = append(results, r+" "+typeString(fset, qualify(field.Type)))
+		}
+	}
+
+	recordArgs := ""
+	if len(args) > 0 {
+		recordArgs = ", " + strings.Join(args, ", ")
+	}
+
+	fmt.Fprintf(w, "\n// %s implements tele.API.\n", name)
+	fmt.Fprintf(w, "func (m *MockAPI) %s(%s) (%s) {\n", name, strings.Join(params, ", "), strings.Join(results, ", "))
+	fmt.Fprintf(w, "\tm.record(%q%s)\n", name, recordArgs)
+	fmt.Fprintf(w, "\tif m.%sFunc != nil {\n", name)
+	if len(names) > 0 {
+		fmt.Fprintf(w, "\t\treturn m.%sFunc(%s)\n", name, strings.Join(callArgs, ", "))
+	} else {
+		fmt.Fprintf(w, "\t\tm.%sFunc(%s)\n", name, strings.Join(callArgs, ", "))
+	}
+	w.WriteString("\t}\n")
+	if len(names) > 0 {
+		w.WriteString("\treturn\n")
+	}
+	w.WriteString("}\n")
+}
+
+func findInterface(f *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != name {
+				continue
+			}
+			if iface, ok := ts.Type.(*ast.InterfaceType); ok {
+				return iface
+			}
+		}
+	}
+	return nil
+}
+
+// qualify prefixes the types declared in the telebot package with tele.
+func qualify(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(e.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("tele"), Sel: ast.NewIdent(e.Name)}
+		}
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key), Value: qualify(e.Value)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: qualify(e.Value)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(e.Params), Results: qualifyFields(e.Results)}
+	}
+	return expr
+}
+
+func qualifyFields(fl *ast.FieldList) *ast.FieldList {
+	if fl == nil {
+		return nil
+	}
+	out := &ast.FieldList{}
+	for _, f := range fl.List {
+		out.List = append(out.List, &ast.Field{Names: f.Names, Type: qualify(f.Type)})
+	}
+	return out
+}
+
+func typeString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
@@ -0,0 +1,627 @@
+package telebottest
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	tele "github.com/irijopa/telebot"
+)
+
+var (
+	errMessageNotFound = &tele.Error{Code: 400, Description: "Bad Request: message to edit not found"}
+	errFileNotFound    = &tele.Error{Code: 400, Description: "Bad Request: invalid file_id"}
+)
+
+// mediaFields maps the sending methods to their file fields.
+var mediaFields = map[string]string{
+	"sendPhoto":     "photo",
+	"sendAudio":     "audio",
+	"sendDocument":  "document",
+	"sendVideo":     "video",
+	"sendAnimation": "animation",
+	"sendVoice":     "voice",
+	"sendVideoNote": "video_note",
+	"sendSticker":   "sticker",
+}
+
+// trueMethods are the methods answered with true.
+var trueMethods = map[string]bool{
+	"answerCallbackQuery":               true,
+	"answerInlineQuery":                 true,
+	"answerPreCheckoutQuery":            true,
+	"answerShippingQuery":               true,
+	"addStickerToSet":                   true,
+	"approveChatJoinRequest":            true,
+	"banChatMember":                     true,
+	"banChatSenderChat":                 true,
+	"close":                             true,
+	"closeForumTopic":                   true,
+	"closeGeneralForumTopic":            true,
+	"createNewStickerSet":               true,
+	"declineChatJoinRequest":            true,
+	"deleteChatPhoto":                   true,
+	"deleteChatStickerSet":              true,
+	"deleteForumTopic":                  true,
+	"deleteMessages":                    true,
+	"deleteMyCommands":                  true,
+	"deleteStickerFromSet":              true,
+	"deleteStickerSet":                  true,
+	"deleteWebhook":                     true,
+	"editForumTopic":                    true,
+	"editGeneralForumTopic":             true,
+	"editUserStarSubscription":          true,
+	"hideGeneralForumTopic":             true,
+	"kickChatMember":                    true,
+	"leaveChat":                         true,
+	"logOut":                            true,
+	"pinChatMessage":                    true,
+	"promoteChatMember":                 true,
+	"refundStarPayment":                 true,
+	"removeChatVerification":            true,
+	"removeUserVerification":            true,
+	"reopenForumTopic":                  true,
+	"reopenGeneralForumTopic":           true,
+	"replaceStickerInSet":               true,
+	"restrictChatMember":                true,
+	"sendChatAction":                    true,
+	"sendGift":                          true,
+	"setChatAdministratorCustomTitle":   true,
+	"setChatDescription":                true,
+	"setChatMenuButton":                 true,
+	"setChatPermissions":                true,
+	"setChatPhoto":                      true,
+	"setChatStickerSet":                 true,
+	"setChatTitle":                      true,
+	"setCustomEmojiStickerSetThumbnail": true,
+	"setMessageReaction":                true,
+	"setMyCommands":                     true,
+	"setMyDefaultAdministratorRights":   true,
+	"setMyDescription":                  true,
+	"setMyName":                         true,
+	"setMyShortDescription":             true,
+	"setStickerEmojiList":               true,
+	"setStickerKeywords":                true,
+	"setStickerMaskPosition":            true,
+	"setStickerPositionInSet":           true,
+	"setStickerSetThumbnail":            true,
+	"setStickerSetTitle":                true,
+	"setUserEmojiStatus":                true,
+	"setWebhook":                        true,
+	"unbanChatMember":                   true,
+	"unbanChatSenderChat":               true,
+	"unhideGeneralForumTopic":           true,
+	"unpinAllChatMessages":              true,
+	"unpinAllForumTopicMessages":        true,
+	"unpinAllGeneralForumTopicMessages": true,
+	"unpinChatMessage":                  true,
+	"verifyChat":                        true,
+	"verifyUser":                        true,
+}
+
+// builtin returns the default answer to the method. The methods
+// returning true are answered with it, the other sending methods with
+// a new message, and the unknown ones with the 404 error. Use Handle
+// to answer them otherwise.
+func (s *Server) builtin(method string) HandlerFunc {
+	if field, ok := mediaFields[method]; ok {
+		return func(r *Request) (interface{}, error) {
+			return s.sendMedia(r, field)
+		}
+	}
+
+	switch method {
+	case "getMe":
+		return func(*Request) (interface{}, error) { return s.Me, nil }
+	case "getUpdates":
+		return s.getUpdates
+	case "sendMessage":
+		return s.sendMessage
+	case "sendMediaGroup":
+		return s.sendMediaGroup
+	case "editMessageText":
+		return s.editMessageText
+	case "editMessageCaption":
+		return s.editMessageCaption
+	case "editMessageReplyMarkup":
+		return s.editMessageReplyMarkup
+	case "deleteMessage":
+		return s.deleteMessage
+	case "forwardMessage":
+		return s.forwardMessage
+	case "copyMessage":
+		return s.copyMessage
+	case "getChat":
+		return s.getChat
+	case "getFile":
+		return s.getFile
+	}
+
+	switch {
+	case trueMethods[method]:
+		return func(*Request) (interface{}, error) { return true, nil }
+	case strings.HasPrefix(method, "send"):
+		return s.send
+	}
+	return func(*Request) (interface{}, error) { return nil, tele.ErrNotFound }
+}
+
+func (s *Server) getUpdates(r *Request) (interface{}, error) {
+	limit, _ := strconv.Atoi(r.Params["limit"])
+	if limit <= 0 {
+		limit = 100
+	}
+	timeout, _ := strconv.Atoi(r.Params["timeout"])
+
+	updates := []tele.Update{}
+	for len(updates) < limit {
+		select {
+		case u := <-s.updates:
+			updates = append(updates, u)
+			continue
+		default:
+		}
+		if len(updates) > 0 || timeout <= 0 {
+			break
+		}
+
+		select {
+		case u := <-s.updates:
+			updates = append(updates, u)
+		case <-time.After(time.Duration(timeout) * time.Second):
+		case <-r.ctx.Done():
+		}
+		break
+	}
+	return updates, nil
+}
+
+func (s *Server) sendMessage(r *Request) (interface{}, error) {
+	if r.Params["text"] == "" {
+		return nil, tele.ErrEmptyMessage
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, err := s.newMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	msg.Text = r.Params["text"]
+	return msg, nil
+}
+
+// send answers the rest of the sending methods with a message,
+// filling the content of the known ones, e.g. sendLocation or sendPoll.
+func (s *Server) send(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, err := s.newMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := r.Params
+	switch r.Method {
+	case "sendLocation":
+		loc := location(p)
+		msg.Location = &loc
+	case "sendVenue":
+		msg.Venue = &tele.Venue{
+			Location:        location(p),
+			Title:           p["title"],
+			Address:         p["address"],
+			FoursquareID:    p["foursquare_id"],
+			FoursquareType:  p["foursquare_type"],
+			GooglePlaceID:   p["google_place_id"],
+			GooglePlaceType: p["google_place_type"],
+		}
+	case "sendContact":
+		msg.Contact = &tele.Contact{
+			PhoneNumber: p["phone_number"],
+			FirstName:   p["first_name"],
+			LastName:    p["last_name"],
+			VCard:       p["vcard"],
+		}
+	case "sendDice":
+		dice := &tele.Dice{Type: tele.DiceType(p["emoji"]), Value: 1}
+		if dice.Type == "" {
+			dice.Type = tele.Cube.Type
+		}
+		msg.Dice = dice
+	case "sendPoll":
+		msg.Poll = &tele.Poll{
+			ID:       strconv.Itoa(msg.ID),
+			Type:     tele.PollType(p["type"]),
+			Question: p["question"],
+			Options:  pollOptions(p["options"]),
+		}
+		if msg.Poll.Type == "" {
+			msg.Poll.Type = tele.PollRegular
+		}
+	}
+	return msg, nil
+}
+
+func location(p map[string]string) tele.Location {
+	lat, _ := strconv.ParseFloat(p["latitude"], 32)
+	lng, _ := strconv.ParseFloat(p["longitude"], 32)
+	return tele.Location{Lat: float32(lat), Lng: float32(lng)}
+}
+
+// pollOptions parses the options passed either as texts or objects.
+func pollOptions(param string) []tele.PollOption {
+	var texts []string
+	if json.Unmarshal([]byte(param), &texts) == nil {
+		opts := make([]tele.PollOption, len(texts))
+		for i, text := range texts {
+			opts[i].Text = text
+		}
+		return opts
+	}
+
+	var opts []tele.PollOption
+	json.Unmarshal([]byte(param), &opts)
+	return opts
+}
+
+func (s *Server) sendMedia(r *Request, field string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, upload, err := s.fileParam(r, field, r.Params[field])
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.newMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	setMedia(msg, field, f, upload, r.Params)
+	return msg, nil
+}
+
+func (s *Server) sendMediaGroup(r *Request) (interface{}, error) {
+	var media []tele.InputMedia
+	if err := json.Unmarshal([]byte(r.Params["media"]), &media); err != nil {
+		return nil, &tele.Error{Code: 400, Description: "Bad Request: can't parse media JSON object"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs := make([]*tele.Message, 0, len(media))
+	for _, im := range media {
+		f, upload, err := s.fileParam(r, im.Type, im.Media)
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err := s.newMessage(r)
+		if err != nil {
+			return nil, err
+		}
+		setMedia(msg, im.Type, f, upload, map[string]string{"caption": im.Caption})
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (s *Server) editMessageText(r *Request) (interface{}, error) {
+	return s.edit(r, func(msg *tele.Message) bool {
+		changed := msg.Text != r.Params["text"]
+		msg.Text = r.Params["text"]
+		return changed
+	})
+}
+
+func (s *Server) editMessageCaption(r *Request) (interface{}, error) {
+	return s.edit(r, func(msg *tele.Message) bool {
+		changed := msg.Caption != r.Params["caption"]
+		msg.Caption = r.Params["caption"]
+		return changed
+	})
+}
+
+func (s *Server) editMessageReplyMarkup(r *Request) (interface{}, error) {
+	return s.edit(r, func(*tele.Message) bool { return false })
+}
+
+// edit applies the change to the message, along with the reply markup.
+// Like the real server, it fails if nothing has been changed.
+func (s *Server) edit(r *Request, change func(*tele.Message) bool) (interface{}, error) {
+	if r.Params["inline_message_id"] != "" {
+		return true, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.findMessage(r.Params["chat_id"], r.Params["message_id"])
+	if msg == nil {
+		return nil, errMessageNotFound
+	}
+
+	edited := *msg
+	changed := change(&edited)
+
+	markup := parseMarkup(r.Params["reply_markup"])
+	if !sameJSON(markup, msg.ReplyMarkup) {
+		changed = true
+	}
+	edited.ReplyMarkup = markup
+
+	if !changed {
+		return nil, tele.ErrSameMessageContent
+	}
+
+	edited.LastEdit = time.Now().Unix()
+	*msg = edited
+	return msg, nil
+}
+
+func (s *Server) deleteMessage(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := s.findMessage(r.Params["chat_id"], r.Params["message_id"])
+	if msg == nil {
+		return nil, tele.ErrNotFoundToDelete
+	}
+	delete(s.messages[msg.Chat.ID], msg.ID)
+	return true, nil
+}
+
+func (s *Server) forwardMessage(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orig := s.findMessage(r.Params["from_chat_id"], r.Params["message_id"])
+	if orig == nil {
+		return nil, tele.ErrNotFoundToForward
+	}
+
+	msg, err := s.newMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	id, chat, sender, date := msg.ID, msg.Chat, msg.Sender, msg.Unixtime
+	*msg = *orig
+	msg.ID, msg.Chat, msg.Sender, msg.Unixtime = id, chat, sender, date
+	msg.OriginalUnixtime = int(orig.Unixtime)
+	return msg, nil
+}
+
+func (s *Server) copyMessage(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orig := s.findMessage(r.Params["from_chat_id"], r.Params["message_id"])
+	if orig == nil {
+		return nil, tele.ErrNotFoundToForward
+	}
+
+	msg, err := s.newMessage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	id, chat, sender, date := msg.ID, msg.Chat, msg.Sender, msg.Unixtime
+	*msg = *orig
+	msg.ID, msg.Chat, msg.Sender, msg.Unixtime = id, chat, sender, date
+	return map[string]int{"message_id": msg.ID}, nil
+}
+
+func (s *Server) getChat(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := strconv.ParseInt(r.Params["chat_id"], 10, 64)
+	if err != nil {
+		return nil, tele.ErrChatNotFound
+	}
+	chat, ok := s.chats[id]
+	if !ok {
+		return nil, tele.ErrChatNotFound
+	}
+	return chat, nil
+}
+
+func (s *Server) getFile(r *Request) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[r.Params["file_id"]]
+	if !ok {
+		return nil, errFileNotFound
+	}
+	return f.File, nil
+}
+
+// newMessage creates a message from the bot to the chat of the request.
+// Must be called with the lock held.
+func (s *Server) newMessage(r *Request) (*tele.Message, error) {
+	chat, err := s.chatParam(r.Params["chat_id"])
+	if err != nil {
+		return nil, err
+	}
+
+	s.lastMessage++
+	me := s.Me
+	msg := &tele.Message{
+		ID:          s.lastMessage,
+		Sender:      &me,
+		Unixtime:    time.Now().Unix(),
+		Chat:        chat,
+		Caption:     r.Params["caption"],
+		ReplyMarkup: parseMarkup(r.Params["reply_markup"]),
+	}
+
+	replyTo := r.Params["reply_to_message_id"]
+	if params := r.Params["reply_parameters"]; params != "" {
+		var rp tele.ReplyParams
+		if json.Unmarshal([]byte(params), &rp) == nil {
+			replyTo = strconv.Itoa(rp.MessageID)
+		}
+	}
+	if replyTo != "" {
+		if orig := s.findMessage(strconv.FormatInt(chat.ID, 10), replyTo); orig != nil {
+			reply := *orig
+			reply.ReplyTo = nil
+			msg.ReplyTo = &reply
+		}
+	}
+
+	s.storeMessage(msg)
+	return msg, nil
+}
+
+// storeMessage must be called with the lock held.
+func (s *Server) storeMessage(msg *tele.Message) {
+	if s.messages[msg.Chat.ID] == nil {
+		s.messages[msg.Chat.ID] = make(map[int]*tele.Message)
+	}
+	s.messages[msg.Chat.ID][msg.ID] = msg
+}
+
+// chatParam returns the chat by its ID or @username, registering
+// the unknown ones. Must be called with the lock held.
+func (s *Server) chatParam(param string) (*tele.Chat, error) {
+	if param == "" {
+		return nil, tele.ErrEmptyChatID
+	}
+
+	if strings.HasPrefix(param, "@") {
+		for _, chat := range s.chats {
+			if chat.Username == param[1:] {
+				return chat, nil
+			}
+		}
+		return nil, tele.ErrChatNotFound
+	}
+
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, tele.ErrChatNotFound
+	}
+
+	chat, ok := s.chats[id]
+	if !ok {
+		chat = &tele.Chat{ID: id, Type: tele.ChatPrivate}
+		if id < 0 {
+			chat.Type = tele.ChatGroup
+		}
+		s.chats[id] = chat
+	}
+	return chat, nil
+}
+
+// findMessage must be called with the lock held.
+func (s *Server) findMessage(chatID, msgID string) *tele.Message {
+	cid, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	mid, err := strconv.Atoi(msgID)
+	if err != nil {
+		return nil
+	}
+	return s.messages[cid][mid]
+}
+
+// fileParam resolves the file passed as the upload, attach:// reference,
+// file ID or URL. Must be called with the lock held.
+func (s *Server) fileParam(r *Request, field, value string) (tele.File, *Upload, error) {
+	upload, ok := r.Files[field]
+	if !ok && strings.HasPrefix(value, "attach://") {
+		upload, ok = r.Files[strings.TrimPrefix(value, "attach://")]
+	}
+
+	switch {
+	case ok:
+		f := s.newFile(upload.Data)
+		return f.File, &upload, nil
+	case value == "":
+		return tele.File{}, nil, &tele.Error{Code: 400, Description: "Bad Request: there is no " + field + " in the request"}
+	case strings.Contains(value, "://"):
+		return s.newFile(nil).File, nil, nil
+	}
+
+	f, ok := s.files[value]
+	if !ok {
+		return tele.File{}, nil, tele.ErrWrongFileID
+	}
+	return f.File, nil, nil
+}
+
+// newFile must be called with the lock held.
+func (s *Server) newFile(data []byte) *file {
+	s.lastFile++
+	id := "file" + strconv.Itoa(s.lastFile)
+
+	f := &file{
+		File: tele.File{
+			FileID:   id,
+			UniqueID: "unique" + strconv.Itoa(s.lastFile),
+			FileSize: int64(len(data)),
+			FilePath: "files/" + id,
+		},
+		data: data,
+	}
+	s.files[id] = f
+	return f
+}
+
+func setMedia(msg *tele.Message, field string, f tele.File, upload *Upload, params map[string]string) {
+	name, mime := params["file_name"], ""
+	if upload != nil {
+		if name == "" {
+			name = upload.Name
+		}
+		mime = upload.MIME
+	}
+
+	// only the file_id is known to the client after sending
+	f.FilePath = ""
+
+	switch field {
+	case "photo":
+		msg.Photo = &tele.Photo{File: f}
+	case "audio":
+		msg.Audio = &tele.Audio{File: f, FileName: name, MIME: mime}
+	case "document":
+		msg.Document = &tele.Document{File: f, FileName: name, MIME: mime}
+	case "video":
+		msg.Video = &tele.Video{File: f, FileName: name, MIME: mime}
+	case "animation":
+		msg.Animation = &tele.Animation{File: f, FileName: name, MIME: mime}
+	case "voice":
+		msg.Voice = &tele.Voice{File: f, MIME: mime}
+	case "video_note":
+		msg.VideoNote = &tele.VideoNote{File: f}
+	case "sticker":
+		msg.Sticker = &tele.Sticker{File: f}
+	}
+}
+
+func parseMarkup(param string) *tele.ReplyMarkup {
+	if param == "" {
+		return nil
+	}
+	var markup tele.ReplyMarkup
+	if json.Unmarshal([]byte(param), &markup) != nil {
+		return nil
+	}
+	return &markup
+}
+
+func sameJSON(a, b interface{}) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
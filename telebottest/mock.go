@@ -0,0 +1,1301 @@
+// Code generated by mockgen from api.go. DO NOT EDIT.
+
+package telebottest
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	tele "github.com/irijopa/telebot"
+)
+
+// MockAPI is a mock of tele.API for unit-testing handlers with a context
+// created by tele.NewContext. Every call is recorded, and the method calls
+// the function field of the same name with the Func suffix, if it's set.
+// Otherwise, zero values are returned.
+//
+//	api := &telebottest.MockAPI{}
+//	api.SendFunc = func(to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error) {
+//		return &tele.Message{}, nil
+//	}
+//
+//	c := tele.NewContext(api, tele.Update{Message: msg})
+//	err := handler(c)
+//	calls := api.CallsOf("Send")
+type MockAPI struct {
+	mu    sync.Mutex
+	calls []Call
+
+	AcceptFunc                   func(query *tele.PreCheckoutQuery, errorMessage ...string) error
+	AddStickerToSetFunc          func(of tele.Recipient, name string, sticker tele.InputSticker) error
+	AdminsOfFunc                 func(chat *tele.Chat) ([]tele.ChatMember, error)
+	AnswerFunc                   func(query *tele.Query, resp *tele.QueryResponse) error
+	AnswerWebAppFunc             func(query *tele.Query, r tele.Result) (*tele.WebAppMessage, error)
+	ApproveJoinRequestFunc       func(chat tele.Recipient, user *tele.User) error
+	BanFunc                      func(chat *tele.Chat, member *tele.ChatMember, revokeMessages ...bool) error
+	BanSenderChatFunc            func(chat *tele.Chat, sender tele.Recipient) error
+	BusinessConnectionFunc       func(id string) (*tele.BusinessConnection, error)
+	ChatByIDFunc                 func(id int64) (*tele.Chat, error)
+	ChatByUsernameFunc           func(name string) (*tele.Chat, error)
+	ChatFullInfoFunc             func(chat tele.Recipient) (*tele.ChatFullInfo, error)
+	ChatMemberOfFunc             func(chat, user tele.Recipient) (*tele.ChatMember, error)
+	CloseFunc                    func() (bool, error)
+	CloseGeneralTopicFunc        func(chat *tele.Chat) error
+	CloseTopicFunc               func(chat *tele.Chat, topic *tele.Topic) error
+	CommandsFunc                 func(opts ...interface{}) ([]tele.Command, error)
+	CopyFunc                     func(to tele.Recipient, msg tele.Editable, opts ...interface{}) (*tele.Message, error)
+	CopyManyFunc                 func(to tele.Recipient, msgs []tele.Editable, opts ...*tele.SendOptions) ([]tele.Message, error)
+	CreateInviteLinkFunc         func(chat tele.Recipient, link *tele.ChatInviteLink) (*tele.ChatInviteLink, error)
+	CreateInvoiceLinkFunc        func(i tele.Invoice) (string, error)
+	CreateStickerSetFunc         func(of tele.Recipient, set *tele.StickerSet) error
+	CreateTopicFunc              func(chat *tele.Chat, topic *tele.Topic) (*tele.Topic, error)
+	CustomEmojiStickersFunc      func(ids []string) ([]tele.Sticker, error)
+	DeclineJoinRequestFunc       func(chat tele.Recipient, user *tele.User) error
+	DefaultRightsFunc            func(forChannels bool) (*tele.Rights, error)
+	DeleteFunc                   func(msg tele.Editable) error
+	DeleteCommandsFunc           func(opts ...interface{}) error
+	DeleteGroupPhotoFunc         func(chat *tele.Chat) error
+	DeleteGroupStickerSetFunc    func(chat *tele.Chat) error
+	DeleteManyFunc               func(msgs []tele.Editable) error
+	DeleteStickerFunc            func(sticker string) error
+	DeleteStickerSetFunc         func(name string) error
+	DeleteTopicFunc              func(chat *tele.Chat, topic *tele.Topic) error
+	DownloadFunc                 func(file *tele.File, localFilename string) error
+	DownloadToFunc               func(file *tele.File, w io.Writer) error
+	EditFunc                     func(msg tele.Editable, what interface{}, opts ...interface{}) (*tele.Message, error)
+	EditCaptionFunc              func(msg tele.Editable, caption string, opts ...interface{}) (*tele.Message, error)
+	EditCaptionProgressFunc      func(msg tele.Editable, every time.Duration, format func(done, total int64) string) tele.ProgressFunc
+	EditGeneralTopicFunc         func(chat *tele.Chat, topic *tele.Topic) error
+	EditInviteLinkFunc           func(chat tele.Recipient, link *tele.ChatInviteLink) (*tele.ChatInviteLink, error)
+	EditMediaFunc                func(msg tele.Editable, media tele.Inputtable, opts ...interface{}) (*tele.Message, error)
+	EditProgressFunc             func(msg tele.Editable, every time.Duration, format func(done, total int64) string) tele.ProgressFunc
+	EditReplyMarkupFunc          func(msg tele.Editable, markup *tele.ReplyMarkup) (*tele.Message, error)
+	EditTopicFunc                func(chat *tele.Chat, topic *tele.Topic) error
+	EditUserStarSubscriptionFunc func(user tele.Recipient, chargeID string, isCanceled bool) error
+	FileFunc                     func(file *tele.File) (io.ReadCloser, error)
+	FileByIDFunc                 func(fileID string) (tele.File, error)
+	ForwardFunc                  func(to tele.Recipient, msg tele.Editable, opts ...interface{}) (*tele.Message, error)
+	ForwardManyFunc              func(to tele.Recipient, msgs []tele.Editable, opts ...*tele.SendOptions) ([]tele.Message, error)
+	GameScoresFunc               func(user tele.Recipient, msg tele.Editable) ([]tele.GameHighScore, error)
+	GetAvailableGiftsFunc        func() ([]tele.Gift,
+
+		error)
+	HideGeneralTopicFunc              func(chat *tele.Chat) error
+	InviteLinkFunc                    func(chat *tele.Chat) (string, error)
+	LeaveFunc                         func(chat tele.Recipient) error
+	LenFunc                           func(chat *tele.Chat) (int, error)
+	LogoutFunc                        func() (bool, error)
+	MenuButtonFunc                    func(chat *tele.User) (*tele.MenuButton, error)
+	MyDescriptionFunc                 func(language string) (*tele.BotInfo, error)
+	MyNameFunc                        func(language string) (*tele.BotInfo, error)
+	MyShortDescriptionFunc            func(language string) (*tele.BotInfo, error)
+	NotifyFunc                        func(to tele.Recipient, action tele.ChatAction, threadID ...int) error
+	PinFunc                           func(msg tele.Editable, opts ...interface{}) error
+	ProfilePhotosOfFunc               func(user *tele.User) ([]tele.Photo, error)
+	PromoteFunc                       func(chat *tele.Chat, member *tele.ChatMember) error
+	RawFunc                           func(method string, payload interface{}) ([]byte, error)
+	ReactFunc                         func(to tele.Recipient, msg tele.Editable, r tele.Reactions) error
+	RefundStarsFunc                   func(to tele.Recipient, chargeID string) error
+	RemoveChatVerificationFunc        func(chat *tele.Chat) error
+	RemoveUserVerificationFunc        func(user tele.Recipient) error
+	RemoveWebhookFunc                 func(dropPending ...bool) error
+	ReopenGeneralTopicFunc            func(chat *tele.Chat) error
+	ReopenTopicFunc                   func(chat *tele.Chat, topic *tele.Topic) error
+	ReplaceStickerInSetFunc           func(of tele.Recipient, stickerSet, oldSticker string, sticker tele.InputSticker) (bool, error)
+	ReplyFunc                         func(to *tele.Message, what interface{}, opts ...interface{}) (*tele.Message, error)
+	RespondFunc                       func(c *tele.Callback, resp ...*tele.CallbackResponse) error
+	RestrictFunc                      func(chat *tele.Chat, member *tele.ChatMember) error
+	RevokeInviteLinkFunc              func(chat tele.Recipient, link string) (*tele.ChatInviteLink, error)
+	SavePreparedInlineMessageFunc     func(user tele.Recipient, result tele.Result, opts ...interface{}) (*tele.PreparedInlineMessage, error)
+	SendFunc                          func(to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error)
+	SendAlbumFunc                     func(to tele.Recipient, a tele.Album, opts ...interface{}) ([]tele.Message, error)
+	SendGiftFunc                      func(to tele.Recipient, giftID string, opts ...interface{}) error
+	SendPaidMediaFunc                 func(to tele.Recipient, stars int, a tele.PaidAlbum, opts ...interface{}) (*tele.Message, error)
+	SetAdminTitleFunc                 func(chat *tele.Chat, user *tele.User, title string) error
+	SetCommandsFunc                   func(opts ...interface{}) error
+	SetCustomEmojiStickerSetThumbFunc func(name, id string) error
+	SetDefaultRightsFunc              func(rights tele.Rights, forChannels bool) error
+	SetGameScoreFunc                  func(user tele.Recipient, msg tele.Editable, score tele.GameHighScore) (*tele.Message, error)
+	SetGroupDescriptionFunc           func(chat *tele.Chat, description string) error
+	SetGroupPermissionsFunc           func(chat *tele.Chat, perms tele.Rights) error
+	SetGroupPhotoFunc                 func(chat *tele.Chat, p *tele.Photo) error
+	SetGroupStickerSetFunc            func(chat *tele.Chat, setName string) error
+	SetGroupTitleFunc                 func(chat *tele.Chat, title string) error
+	SetMenuButtonFunc                 func(chat *tele.User, mb interface{}) error
+	SetMyDescriptionFunc              func(desc, language string) error
+	SetMyNameFunc                     func(name, language string) error
+	SetMyShortDescriptionFunc         func(desc, language string) error
+	SetStickerEmojisFunc              func(sticker string, emojis []string) error
+	SetStickerKeywordsFunc            func(sticker string, keywords []string) error
+	SetStickerMaskPositionFunc        func(sticker string, mask tele.MaskPosition) error
+	SetStickerPositionFunc            func(sticker string, position int) error
+	SetStickerSetThumbFunc            func(of tele.Recipient, set *tele.StickerSet) error
+	SetStickerSetTitleFunc            func(s tele.StickerSet) error
+	SetUserEmojiStatusFunc            func(user tele.Recipient, emojiStatusCustomEmojiID string, expirationDate ...int64) error
+	SetWebhookFunc                    func(w *tele.Webhook) error
+	ShipFunc                          func(query *tele.ShippingQuery, what ...interface{}) error
+	StarTransactionsFunc              func(offset, limit int) ([]tele.StarTransaction, error)
+	StickerSetFunc                    func(name string) (*tele.StickerSet, error)
+	StopLiveLocationFunc              func(msg tele.Editable, opts ...interface{}) (*tele.Message, error)
+	StopPollFunc                      func(msg tele.Editable, opts ...interface{}) (*tele.Poll, error)
+	TopicIconStickersFunc             func() ([]tele.Sticker,
+
+		error)
+	UnbanFunc                        func(chat *tele.Chat, user *tele.User, forBanned ...bool) error
+	UnbanSenderChatFunc              func(chat *tele.Chat, sender tele.Recipient) error
+	UnhideGeneralTopicFunc           func(chat *tele.Chat) error
+	UnpinFunc                        func(chat tele.Recipient, messageID ...int) error
+	UnpinAllFunc                     func(chat tele.Recipient) error
+	UnpinAllGeneralTopicMessagesFunc func(chat *tele.Chat) error
+	UnpinAllTopicMessagesFunc        func(chat *tele.Chat, topic *tele.Topic) error
+	UploadStickerFunc                func(to tele.Recipient, format tele.StickerSetFormat, f tele.File) (*tele.File, error)
+	UserBoostsFunc                   func(chat, user tele.Recipient) ([]tele.Boost, error)
+	VerifyChatFunc                   func(chat *tele.Chat, level tele.VerificationLevel, requirements *tele.VerificationRequirements) error
+	VerifyUserFunc                   func(user tele.Recipient, level tele.VerificationLevel, requirements *tele.VerificationRequirements) error
+	WebhookFunc                      func() (*tele.Webhook,
+
+		error)
+}
+
+var _ tele.API = (*MockAPI)(nil)
+
+// Call is a recorded call of the MockAPI method.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Calls returns all the recorded calls.
+func (m *MockAPI) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsOf returns the recorded calls of the method.
+func (m *MockAPI) CallsOf(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []Call
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+func (m *MockAPI) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	m.mu.Unlock()
+}
+
+// Accept implements tele.API.
+func (m *MockAPI) Accept(query *tele.PreCheckoutQuery, errorMessage ...string) (r0 error) {
+	m.record("Accept", query, errorMessage)
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(query, errorMessage...)
+	}
+	return
+}
+
+// AddStickerToSet implements tele.API.
+func (m *MockAPI) AddStickerToSet(of tele.Recipient, name string, sticker tele.InputSticker) (r0 error) {
+	m.record("AddStickerToSet", of, name, sticker)
+	if m.AddStickerToSetFunc != nil {
+		return m.AddStickerToSetFunc(of, name, sticker)
+	}
+	return
+}
+
+// AdminsOf implements tele.API.
+func (m *MockAPI) AdminsOf(chat *tele.Chat) (r0 []tele.ChatMember, r1 error) {
+	m.record("AdminsOf", chat)
+	if m.AdminsOfFunc != nil {
+		return m.AdminsOfFunc(chat)
+	}
+	return
+}
+
+// Answer implements tele.API.
+func (m *MockAPI) Answer(query *tele.Query, resp *tele.QueryResponse) (r0 error) {
+	m.record("Answer", query, resp)
+	if m.AnswerFunc != nil {
+		return m.AnswerFunc(query, resp)
+	}
+	return
+}
+
+// AnswerWebApp implements tele.API.
+func (m *MockAPI) AnswerWebApp(query *tele.Query, r tele.Result) (r0 *tele.WebAppMessage, r1 error) {
+	m.record("AnswerWebApp", query, r)
+	if m.AnswerWebAppFunc != nil {
+		return m.AnswerWebAppFunc(query, r)
+	}
+	return
+}
+
+// ApproveJoinRequest implements tele.API.
+func (m *MockAPI) ApproveJoinRequest(chat tele.Recipient, user *tele.User) (r0 error) {
+	m.record("ApproveJoinRequest", chat, user)
+	if m.ApproveJoinRequestFunc != nil {
+		return m.ApproveJoinRequestFunc(chat, user)
+	}
+	return
+}
+
+// Ban implements tele.API.
+func (m *MockAPI) Ban(chat *tele.Chat, member *tele.ChatMember, revokeMessages ...bool) (r0 error) {
+	m.record("Ban", chat, member, revokeMessages)
+	if m.BanFunc != nil {
+		return m.BanFunc(chat, member, revokeMessages...)
+	}
+	return
+}
+
+// BanSenderChat implements tele.API.
+func (m *MockAPI) BanSenderChat(chat *tele.Chat, sender tele.Recipient) (r0 error) {
+	m.record("BanSenderChat", chat, sender)
+	if m.BanSenderChatFunc != nil {
+		return m.BanSenderChatFunc(chat, sender)
+	}
+	return
+}
+
+// BusinessConnection implements tele.API.
+func (m *MockAPI) BusinessConnection(id string) (r0 *tele.BusinessConnection, r1 error) {
+	m.record("BusinessConnection", id)
+	if m.BusinessConnectionFunc != nil {
+		return m.BusinessConnectionFunc(id)
+	}
+	return
+}
+
+// ChatByID implements tele.API.
+func (m *MockAPI) ChatByID(id int64) (r0 *tele.Chat, r1 error) {
+	m.record("ChatByID", id)
+	if m.ChatByIDFunc != nil {
+		return m.ChatByIDFunc(id)
+	}
+	return
+}
+
+// ChatByUsername implements tele.API.
+func (m *MockAPI) ChatByUsername(name string) (r0 *tele.Chat, r1 error) {
+	m.record("ChatByUsername", name)
+	if m.ChatByUsernameFunc != nil {
+		return m.ChatByUsernameFunc(name)
+	}
+	return
+}
+
+// ChatFullInfo implements tele.API.
+func (m *MockAPI) ChatFullInfo(chat tele.Recipient) (r0 *tele.ChatFullInfo, r1 error) {
+	m.record("ChatFullInfo", chat)
+	if m.ChatFullInfoFunc != nil {
+		return m.ChatFullInfoFunc(chat)
+	}
+	return
+}
+
+// ChatMemberOf implements tele.API.
+func (m *MockAPI) ChatMemberOf(chat tele.Recipient, user tele.Recipient) (r0 *tele.ChatMember, r1 error) {
+	m.record("ChatMemberOf", chat, user)
+	if m.ChatMemberOfFunc != nil {
+		return m.ChatMemberOfFunc(chat, user)
+	}
+	return
+}
+
+// Close implements tele.API.
+func (m *MockAPI) Close() (r0 bool, r1 error) {
+	m.record("Close")
+	if m.CloseFunc != nil {
+		return m.CloseFunc()
+	}
+	return
+}
+
+// CloseGeneralTopic implements tele.API.
+func (m *MockAPI) CloseGeneralTopic(chat *tele.Chat) (r0 error) {
+	m.record("CloseGeneralTopic", chat)
+	if m.CloseGeneralTopicFunc != nil {
+		return m.CloseGeneralTopicFunc(chat)
+	}
+	return
+}
+
+// CloseTopic implements tele.API.
+func (m *MockAPI) CloseTopic(chat *tele.Chat, topic *tele.Topic) (r0 error) {
+	m.record("CloseTopic", chat, topic)
+	if m.CloseTopicFunc != nil {
+		return m.CloseTopicFunc(chat, topic)
+	}
+	return
+}
+
+// Commands implements tele.API.
+func (m *MockAPI) Commands(opts ...interface{}) (r0 []tele.Command, r1 error) {
+	m.record("Commands", opts)
+	if m.CommandsFunc != nil {
+		return m.CommandsFunc(opts...)
+	}
+	return
+}
+
+// Copy implements tele.API.
+func (m *MockAPI) Copy(to tele.Recipient, msg tele.Editable, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("Copy", to, msg, opts)
+	if m.CopyFunc != nil {
+		return m.CopyFunc(to, msg, opts...)
+	}
+	return
+}
+
+// CopyMany implements tele.API.
+func (m *MockAPI) CopyMany(to tele.Recipient, msgs []tele.Editable, opts ...*tele.SendOptions) (r0 []tele.Message, r1 error) {
+	m.record("CopyMany", to, msgs, opts)
+	if m.CopyManyFunc != nil {
+		return m.CopyManyFunc(to, msgs, opts...)
+	}
+	return
+}
+
+// CreateInviteLink implements tele.API.
+func (m *MockAPI) CreateInviteLink(chat tele.Recipient, link *tele.ChatInviteLink) (r0 *tele.ChatInviteLink, r1 error) {
+	m.record("CreateInviteLink", chat, link)
+	if m.CreateInviteLinkFunc != nil {
+		return m.CreateInviteLinkFunc(chat, link)
+	}
+	return
+}
+
+// CreateInvoiceLink implements tele.API.
+func (m *MockAPI) CreateInvoiceLink(i tele.Invoice) (r0 string, r1 error) {
+	m.record("CreateInvoiceLink", i)
+	if m.CreateInvoiceLinkFunc != nil {
+		return m.CreateInvoiceLinkFunc(i)
+	}
+	return
+}
+
+// CreateStickerSet implements tele.API.
+func (m *MockAPI) CreateStickerSet(of tele.Recipient, set *tele.StickerSet) (r0 error) {
+	m.record("CreateStickerSet", of, set)
+	if m.CreateStickerSetFunc != nil {
+		return m.CreateStickerSetFunc(of, set)
+	}
+	return
+}
+
+// CreateTopic implements tele.API.
+func (m *MockAPI) CreateTopic(chat *tele.Chat, topic *tele.Topic) (r0 *tele.Topic, r1 error) {
+	m.record("CreateTopic", chat, topic)
+	if m.CreateTopicFunc != nil {
+		return m.CreateTopicFunc(chat, topic)
+	}
+	return
+}
+
+// CustomEmojiStickers implements tele.API.
+func (m *MockAPI) CustomEmojiStickers(ids []string) (r0 []tele.Sticker, r1 error) {
+	m.record("CustomEmojiStickers", ids)
+	if m.CustomEmojiStickersFunc != nil {
+		return m.CustomEmojiStickersFunc(ids)
+	}
+	return
+}
+
+// DeclineJoinRequest implements tele.API.
+func (m *MockAPI) DeclineJoinRequest(chat tele.Recipient, user *tele.User) (r0 error) {
+	m.record("DeclineJoinRequest", chat, user)
+	if m.DeclineJoinRequestFunc != nil {
+		return m.DeclineJoinRequestFunc(chat, user)
+	}
+	return
+}
+
+// DefaultRights implements tele.API.
+func (m *MockAPI) DefaultRights(forChannels bool) (r0 *tele.Rights, r1 error) {
+	m.record("DefaultRights", forChannels)
+	if m.DefaultRightsFunc != nil {
+		return m.DefaultRightsFunc(forChannels)
+	}
+	return
+}
+
+// Delete implements tele.API.
+func (m *MockAPI) Delete(msg tele.Editable) (r0 error) {
+	m.record("Delete", msg)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(msg)
+	}
+	return
+}
+
+// DeleteCommands implements tele.API.
+func (m *MockAPI) DeleteCommands(opts ...interface{}) (r0 error) {
+	m.record("DeleteCommands", opts)
+	if m.DeleteCommandsFunc != nil {
+		return m.DeleteCommandsFunc(opts...)
+	}
+	return
+}
+
+// DeleteGroupPhoto implements tele.API.
+func (m *MockAPI) DeleteGroupPhoto(chat *tele.Chat) (r0 error) {
+	m.record("DeleteGroupPhoto", chat)
+	if m.DeleteGroupPhotoFunc != nil {
+		return m.DeleteGroupPhotoFunc(chat)
+	}
+	return
+}
+
+// DeleteGroupStickerSet implements tele.API.
+func (m *MockAPI) DeleteGroupStickerSet(chat *tele.Chat) (r0 error) {
+	m.record("DeleteGroupStickerSet", chat)
+	if m.DeleteGroupStickerSetFunc != nil {
+		return m.DeleteGroupStickerSetFunc(chat)
+	}
+	return
+}
+
+// DeleteMany implements tele.API.
+func (m *MockAPI) DeleteMany(msgs []tele.Editable) (r0 error) {
+	m.record("DeleteMany", msgs)
+	if m.DeleteManyFunc != nil {
+		return m.DeleteManyFunc(msgs)
+	}
+	return
+}
+
+// DeleteSticker implements tele.API.
+func (m *MockAPI) DeleteSticker(sticker string) (r0 error) {
+	m.record("DeleteSticker", sticker)
+	if m.DeleteStickerFunc != nil {
+		return m.DeleteStickerFunc(sticker)
+	}
+	return
+}
+
+// DeleteStickerSet implements tele.API.
+func (m *MockAPI) DeleteStickerSet(name string) (r0 error) {
+	m.record("DeleteStickerSet", name)
+	if m.DeleteStickerSetFunc != nil {
+		return m.DeleteStickerSetFunc(name)
+	}
+	return
+}
+
+// DeleteTopic implements tele.API.
+func (m *MockAPI) DeleteTopic(chat *tele.Chat, topic *tele.Topic) (r0 error) {
+	m.record("DeleteTopic", chat, topic)
+	if m.DeleteTopicFunc != nil {
+		return m.DeleteTopicFunc(chat, topic)
+	}
+	return
+}
+
+// Download implements tele.API.
+func (m *MockAPI) Download(file *tele.File, localFilename string) (r0 error) {
+	m.record("Download", file, localFilename)
+	if m.DownloadFunc != nil {
+		return m.DownloadFunc(file, localFilename)
+	}
+	return
+}
+
+// DownloadTo implements tele.API.
+func (m *MockAPI) DownloadTo(file *tele.File, w io.Writer) (r0 error) {
+	m.record("DownloadTo", file, w)
+	if m.DownloadToFunc != nil {
+		return m.DownloadToFunc(file, w)
+	}
+	return
+}
+
+// Edit implements tele.API.
+func (m *MockAPI) Edit(msg tele.Editable, what interface{}, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("Edit", msg, what, opts)
+	if m.EditFunc != nil {
+		return m.EditFunc(msg, what, opts...)
+	}
+	return
+}
+
+// EditCaption implements tele.API.
+func (m *MockAPI) EditCaption(msg tele.Editable, caption string, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("EditCaption", msg, caption, opts)
+	if m.EditCaptionFunc != nil {
+		return m.EditCaptionFunc(msg, caption, opts...)
+	}
+	return
+}
+
+// EditCaptionProgress implements tele.API.
+func (m *MockAPI) EditCaptionProgress(msg tele.Editable, every time.Duration, format func(done, total int64) string) (r0 tele.ProgressFunc) {
+	m.record("EditCaptionProgress", msg, every, format)
+	if m.EditCaptionProgressFunc != nil {
+		return m.EditCaptionProgressFunc(msg, every, format)
+	}
+	return
+}
+
+// EditGeneralTopic implements tele.API.
+func (m *MockAPI) EditGeneralTopic(chat *tele.Chat, topic *tele.Topic) (r0 error) {
+	m.record("EditGeneralTopic", chat, topic)
+	if m.EditGeneralTopicFunc != nil {
+		return m.EditGeneralTopicFunc(chat, topic)
+	}
+	return
+}
+
+// EditInviteLink implements tele.API.
+func (m *MockAPI) EditInviteLink(chat tele.Recipient, link *tele.ChatInviteLink) (r0 *tele.ChatInviteLink, r1 error) {
+	m.record("EditInviteLink", chat, link)
+	if m.EditInviteLinkFunc != nil {
+		return m.EditInviteLinkFunc(chat, link)
+	}
+	return
+}
+
+// EditMedia implements tele.API.
+func (m *MockAPI) EditMedia(msg tele.Editable, media tele.Inputtable, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("EditMedia", msg, media, opts)
+	if m.EditMediaFunc != nil {
+		return m.EditMediaFunc(msg, media, opts...)
+	}
+	return
+}
+
+// EditProgress implements tele.API.
+func (m *MockAPI) EditProgress(msg tele.Editable, every time.Duration, format func(done, total int64) string) (r0 tele.ProgressFunc) {
+	m.record("EditProgress", msg, every, format)
+	if m.EditProgressFunc != nil {
+		return m.EditProgressFunc(msg, every, format)
+	}
+	return
+}
+
+// EditReplyMarkup implements tele.API.
+func (m *MockAPI) EditReplyMarkup(msg tele.Editable, markup *tele.ReplyMarkup) (r0 *tele.Message, r1 error) {
+	m.record("EditReplyMarkup", msg, markup)
+	if m.EditReplyMarkupFunc != nil {
+		return m.EditReplyMarkupFunc(msg, markup)
+	}
+	return
+}
+
+// EditTopic implements tele.API.
+func (m *MockAPI) EditTopic(chat *tele.Chat, topic *tele.Topic) (r0 error) {
+	m.record("EditTopic", chat, topic)
+	if m.EditTopicFunc != nil {
+		return m.EditTopicFunc(chat, topic)
+	}
+	return
+}
+
+// EditUserStarSubscription implements tele.API.
+func (m *MockAPI) EditUserStarSubscription(user tele.Recipient, chargeID string, isCanceled bool) (r0 error) {
+	m.record("EditUserStarSubscription", user, chargeID, isCanceled)
+	if m.EditUserStarSubscriptionFunc != nil {
+		return m.EditUserStarSubscriptionFunc(user, chargeID, isCanceled)
+	}
+	return
+}
+
+// File implements tele.API.
+func (m *MockAPI) File(file *tele.File) (r0 io.ReadCloser, r1 error) {
+	m.record("File", file)
+	if m.FileFunc != nil {
+		return m.FileFunc(file)
+	}
+	return
+}
+
+// FileByID implements tele.API.
+func (m *MockAPI) FileByID(fileID string) (r0 tele.File, r1 error) {
+	m.record("FileByID", fileID)
+	if m.FileByIDFunc != nil {
+		return m.FileByIDFunc(fileID)
+	}
+	return
+}
+
+// Forward implements tele.API.
+func (m *MockAPI) Forward(to tele.Recipient, msg tele.Editable, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("Forward", to, msg, opts)
+	if m.ForwardFunc != nil {
+		return m.ForwardFunc(to, msg, opts...)
+	}
+	return
+}
+
+// ForwardMany implements tele.API.
+func (m *MockAPI) ForwardMany(to tele.Recipient, msgs []tele.Editable, opts ...*tele.SendOptions) (r0 []tele.Message, r1 error) {
+	m.record("ForwardMany", to, msgs, opts)
+	if m.ForwardManyFunc != nil {
+		return m.ForwardManyFunc(to, msgs, opts...)
+	}
+	return
+}
+
+// GameScores implements tele.API.
+func (m *MockAPI) GameScores(user tele.Recipient, msg tele.Editable) (r0 []tele.GameHighScore, r1 error) {
+	m.record("GameScores", user, msg)
+	if m.GameScoresFunc != nil {
+		return m.GameScoresFunc(user, msg)
+	}
+	return
+}
+
+// GetAvailableGifts implements tele.API.
+func (m *MockAPI) GetAvailableGifts() (r0 []tele.Gift, r1 error) {
+	m.record("GetAvailableGifts")
+	if m.GetAvailableGiftsFunc != nil {
+		return m.GetAvailableGiftsFunc()
+	}
+	return
+}
+
+// HideGeneralTopic implements tele.API.
+func (m *MockAPI) HideGeneralTopic(chat *tele.Chat) (r0 error) {
+	m.record("HideGeneralTopic", chat)
+	if m.HideGeneralTopicFunc != nil {
+		return m.HideGeneralTopicFunc(chat)
+	}
+	return
+}
+
+// InviteLink implements tele.API.
+func (m *MockAPI) InviteLink(chat *tele.Chat) (r0 string, r1 error) {
+	m.record("InviteLink", chat)
+	if m.InviteLinkFunc != nil {
+		return m.InviteLinkFunc(chat)
+	}
+	return
+}
+
+// Leave implements tele.API.
+func (m *MockAPI) Leave(chat tele.Recipient) (r0 error) {
+	m.record("Leave", chat)
+	if m.LeaveFunc != nil {
+		return m.LeaveFunc(chat)
+	}
+	return
+}
+
+// Len implements tele.API.
+func (m *MockAPI) Len(chat *tele.Chat) (r0 int, r1 error) {
+	m.record("Len", chat)
+	if m.LenFunc != nil {
+		return m.LenFunc(chat)
+	}
+	return
+}
+
+// Logout implements tele.API.
+func (m *MockAPI) Logout() (r0 bool, r1 error) {
+	m.record("Logout")
+	if m.LogoutFunc != nil {
+		return m.LogoutFunc()
+	}
+	return
+}
+
+// MenuButton implements tele.API.
+func (m *MockAPI) MenuButton(chat *tele.User) (r0 *tele.MenuButton, r1 error) {
+	m.record("MenuButton", chat)
+	if m.MenuButtonFunc != nil {
+		return m.MenuButtonFunc(chat)
+	}
+	return
+}
+
+// MyDescription implements tele.API.
+func (m *MockAPI) MyDescription(language string) (r0 *tele.BotInfo, r1 error) {
+	m.record("MyDescription", language)
+	if m.MyDescriptionFunc != nil {
+		return m.MyDescriptionFunc(language)
+	}
+	return
+}
+
+// MyName implements tele.API.
+func (m *MockAPI) MyName(language string) (r0 *tele.BotInfo, r1 error) {
+	m.record("MyName", language)
+	if m.MyNameFunc != nil {
+		return m.MyNameFunc(language)
+	}
+	return
+}
+
+// MyShortDescription implements tele.API.
+func (m *MockAPI) MyShortDescription(language string) (r0 *tele.BotInfo, r1 error) {
+	m.record("MyShortDescription", language)
+	if m.MyShortDescriptionFunc != nil {
+		return m.MyShortDescriptionFunc(language)
+	}
+	return
+}
+
+// Notify implements tele.API.
+func (m *MockAPI) Notify(to tele.Recipient, action tele.ChatAction, threadID ...int) (r0 error) {
+	m.record("Notify", to, action, threadID)
+	if m.NotifyFunc != nil {
+		return m.NotifyFunc(to, action, threadID...)
+	}
+	return
+}
+
+// Pin implements tele.API.
+func (m *MockAPI) Pin(msg tele.Editable, opts ...interface{}) (r0 error) {
+	m.record("Pin", msg, opts)
+	if m.PinFunc != nil {
+		return m.PinFunc(msg, opts...)
+	}
+	return
+}
+
+// ProfilePhotosOf implements tele.API.
+func (m *MockAPI) ProfilePhotosOf(user *tele.User) (r0 []tele.Photo, r1 error) {
+	m.record("ProfilePhotosOf", user)
+	if m.ProfilePhotosOfFunc != nil {
+		return m.ProfilePhotosOfFunc(user)
+	}
+	return
+}
+
+// Promote implements tele.API.
+func (m *MockAPI) Promote(chat *tele.Chat, member *tele.ChatMember) (r0 error) {
+	m.record("Promote", chat, member)
+	if m.PromoteFunc != nil {
+		return m.PromoteFunc(chat, member)
+	}
+	return
+}
+
+// Raw implements tele.API.
+func (m *MockAPI) Raw(method string, payload interface{}) (r0 []byte, r1 error) {
+	m.record("Raw", method, payload)
+	if m.RawFunc != nil {
+		return m.RawFunc(method, payload)
+	}
+	return
+}
+
+// React implements tele.API.
+func (m *MockAPI) React(to tele.Recipient, msg tele.Editable, r tele.Reactions) (r0 error) {
+	m.record("React", to, msg, r)
+	if m.ReactFunc != nil {
+		return m.ReactFunc(to, msg, r)
+	}
+	return
+}
+
+// RefundStars implements tele.API.
+func (m *MockAPI) RefundStars(to tele.Recipient, chargeID string) (r0 error) {
+	m.record("RefundStars", to, chargeID)
+	if m.RefundStarsFunc != nil {
+		return m.RefundStarsFunc(to, chargeID)
+	}
+	return
+}
+
+// RemoveChatVerification implements tele.API.
+func (m *MockAPI) RemoveChatVerification(chat *tele.Chat) (r0 error) {
+	m.record("RemoveChatVerification", chat)
+	if m.RemoveChatVerificationFunc != nil {
+		return m.RemoveChatVerificationFunc(chat)
+	}
+	return
+}
+
+// RemoveUserVerification implements tele.API.
+func (m *MockAPI) RemoveUserVerification(user tele.Recipient) (r0 error) {
+	m.record("RemoveUserVerification", user)
+	if m.RemoveUserVerificationFunc != nil {
+		return m.RemoveUserVerificationFunc(user)
+	}
+	return
+}
+
+// RemoveWebhook implements tele.API.
+func (m *MockAPI) RemoveWebhook(dropPending ...bool) (r0 error) {
+	m.record("RemoveWebhook", dropPending)
+	if m.RemoveWebhookFunc != nil {
+		return m.RemoveWebhookFunc(dropPending...)
+	}
+	return
+}
+
+// ReopenGeneralTopic implements tele.API.
+func (m *MockAPI) ReopenGeneralTopic(chat *tele.Chat) (r0 error) {
+	m.record("ReopenGeneralTopic", chat)
+	if m.ReopenGeneralTopicFunc != nil {
+		return m.ReopenGeneralTopicFunc(chat)
+	}
+	return
+}
+
+// ReopenTopic implements tele.API.
+func (m *MockAPI) ReopenTopic(chat *tele.Chat, topic *tele.Topic) (r0 error) {
+	m.record("ReopenTopic", chat, topic)
+	if m.ReopenTopicFunc != nil {
+		return m.ReopenTopicFunc(chat, topic)
+	}
+	return
+}
+
+// ReplaceStickerInSet implements tele.API.
+func (m *MockAPI) ReplaceStickerInSet(of tele.Recipient, stickerSet string, oldSticker string, sticker tele.InputSticker) (r0 bool, r1 error) {
+	m.record("ReplaceStickerInSet", of, stickerSet, oldSticker, sticker)
+	if m.ReplaceStickerInSetFunc != nil {
+		return m.ReplaceStickerInSetFunc(of, stickerSet, oldSticker, sticker)
+	}
+	return
+}
+
+// Reply implements tele.API.
+func (m *MockAPI) Reply(to *tele.Message, what interface{}, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("Reply", to, what, opts)
+	if m.ReplyFunc != nil {
+		return m.ReplyFunc(to, what, opts...)
+	}
+	return
+}
+
+// Respond implements tele.API.
+func (m *MockAPI) Respond(c *tele.Callback, resp ...*tele.CallbackResponse) (r0 error) {
+	m.record("Respond", c, resp)
+	if m.RespondFunc != nil {
+		return m.RespondFunc(c, resp...)
+	}
+	return
+}
+
+// Restrict implements tele.API.
+func (m *MockAPI) Restrict(chat *tele.Chat, member *tele.ChatMember) (r0 error) {
+	m.record("Restrict", chat, member)
+	if m.RestrictFunc != nil {
+		return m.RestrictFunc(chat, member)
+	}
+	return
+}
+
+// RevokeInviteLink implements tele.API.
+func (m *MockAPI) RevokeInviteLink(chat tele.Recipient, link string) (r0 *tele.ChatInviteLink, r1 error) {
+	m.record("RevokeInviteLink", chat, link)
+	if m.RevokeInviteLinkFunc != nil {
+		return m.RevokeInviteLinkFunc(chat, link)
+	}
+	return
+}
+
+// SavePreparedInlineMessage implements tele.API.
+func (m *MockAPI) SavePreparedInlineMessage(user tele.Recipient, result tele.Result, opts ...interface{}) (r0 *tele.PreparedInlineMessage, r1 error) {
+	m.record("SavePreparedInlineMessage", user, result, opts)
+	if m.SavePreparedInlineMessageFunc != nil {
+		return m.SavePreparedInlineMessageFunc(user, result, opts...)
+	}
+	return
+}
+
+// Send implements tele.API.
+func (m *MockAPI) Send(to tele.Recipient, what interface{}, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("Send", to, what, opts)
+	if m.SendFunc != nil {
+		return m.SendFunc(to, what, opts...)
+	}
+	return
+}
+
+// SendAlbum implements tele.API.
+func (m *MockAPI) SendAlbum(to tele.Recipient, a tele.Album, opts ...interface{}) (r0 []tele.Message, r1 error) {
+	m.record("SendAlbum", to, a, opts)
+	if m.SendAlbumFunc != nil {
+		return m.SendAlbumFunc(to, a, opts...)
+	}
+	return
+}
+
+// SendGift implements tele.API.
+func (m *MockAPI) SendGift(to tele.Recipient, giftID string, opts ...interface{}) (r0 error) {
+	m.record("SendGift", to, giftID, opts)
+	if m.SendGiftFunc != nil {
+		return m.SendGiftFunc(to, giftID, opts...)
+	}
+	return
+}
+
+// SendPaidMedia implements tele.API.
+func (m *MockAPI) SendPaidMedia(to tele.Recipient, stars int, a tele.PaidAlbum, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("SendPaidMedia", to, stars, a, opts)
+	if m.SendPaidMediaFunc != nil {
+		return m.SendPaidMediaFunc(to, stars, a, opts...)
+	}
+	return
+}
+
+// SetAdminTitle implements tele.API.
+func (m *MockAPI) SetAdminTitle(chat *tele.Chat, user *tele.User, title string) (r0 error) {
+	m.record("SetAdminTitle", chat, user, title)
+	if m.SetAdminTitleFunc != nil {
+		return m.SetAdminTitleFunc(chat, user, title)
+	}
+	return
+}
+
+// SetCommands implements tele.API.
+func (m *MockAPI) SetCommands(opts ...interface{}) (r0 error) {
+	m.record("SetCommands", opts)
+	if m.SetCommandsFunc != nil {
+		return m.SetCommandsFunc(opts...)
+	}
+	return
+}
+
+// SetCustomEmojiStickerSetThumb implements tele.API.
+func (m *MockAPI) SetCustomEmojiStickerSetThumb(name string, id string) (r0 error) {
+	m.record("SetCustomEmojiStickerSetThumb", name, id)
+	if m.SetCustomEmojiStickerSetThumbFunc != nil {
+		return m.SetCustomEmojiStickerSetThumbFunc(name, id)
+	}
+	return
+}
+
+// SetDefaultRights implements tele.API.
+func (m *MockAPI) SetDefaultRights(rights tele.Rights, forChannels bool) (r0 error) {
+	m.record("SetDefaultRights", rights, forChannels)
+	if m.SetDefaultRightsFunc != nil {
+		return m.SetDefaultRightsFunc(rights, forChannels)
+	}
+	return
+}
+
+// SetGameScore implements tele.API.
+func (m *MockAPI) SetGameScore(user tele.Recipient, msg tele.Editable, score tele.GameHighScore) (r0 *tele.Message, r1 error) {
+	m.record("SetGameScore", user, msg, score)
+	if m.SetGameScoreFunc != nil {
+		return m.SetGameScoreFunc(user, msg, score)
+	}
+	return
+}
+
+// SetGroupDescription implements tele.API.
+func (m *MockAPI) SetGroupDescription(chat *tele.Chat, description string) (r0 error) {
+	m.record("SetGroupDescription", chat, description)
+	if m.SetGroupDescriptionFunc != nil {
+		return m.SetGroupDescriptionFunc(chat, description)
+	}
+	return
+}
+
+// SetGroupPermissions implements tele.API.
+func (m *MockAPI) SetGroupPermissions(chat *tele.Chat, perms tele.Rights) (r0 error) {
+	m.record("SetGroupPermissions", chat, perms)
+	if m.SetGroupPermissionsFunc != nil {
+		return m.SetGroupPermissionsFunc(chat, perms)
+	}
+	return
+}
+
+// SetGroupPhoto implements tele.API.
+func (m *MockAPI) SetGroupPhoto(chat *tele.Chat, p *tele.Photo) (r0 error) {
+	m.record("SetGroupPhoto", chat, p)
+	if m.SetGroupPhotoFunc != nil {
+		return m.SetGroupPhotoFunc(chat, p)
+	}
+	return
+}
+
+// SetGroupStickerSet implements tele.API.
+func (m *MockAPI) SetGroupStickerSet(chat *tele.Chat, setName string) (r0 error) {
+	m.record("SetGroupStickerSet", chat, setName)
+	if m.SetGroupStickerSetFunc != nil {
+		return m.SetGroupStickerSetFunc(chat, setName)
+	}
+	return
+}
+
+// SetGroupTitle implements tele.API.
+func (m *MockAPI) SetGroupTitle(chat *tele.Chat, title string) (r0 error) {
+	m.record("SetGroupTitle", chat, title)
+	if m.SetGroupTitleFunc != nil {
+		return m.SetGroupTitleFunc(chat, title)
+	}
+	return
+}
+
+// SetMenuButton implements tele.API.
+func (m *MockAPI) SetMenuButton(chat *tele.User, mb interface{}) (r0 error) {
+	m.record("SetMenuButton", chat, mb)
+	if m.SetMenuButtonFunc != nil {
+		return m.SetMenuButtonFunc(chat, mb)
+	}
+	return
+}
+
+// SetMyDescription implements tele.API.
+func (m *MockAPI) SetMyDescription(desc string, language string) (r0 error) {
+	m.record("SetMyDescription", desc, language)
+	if m.SetMyDescriptionFunc != nil {
+		return m.SetMyDescriptionFunc(desc, language)
+	}
+	return
+}
+
+// SetMyName implements tele.API.
+func (m *MockAPI) SetMyName(name string, language string) (r0 error) {
+	m.record("SetMyName", name, language)
+	if m.SetMyNameFunc != nil {
+		return m.SetMyNameFunc(name, language)
+	}
+	return
+}
+
+// SetMyShortDescription implements tele.API.
+func (m *MockAPI) SetMyShortDescription(desc string, language string) (r0 error) {
+	m.record("SetMyShortDescription", desc, language)
+	if m.SetMyShortDescriptionFunc != nil {
+		return m.SetMyShortDescriptionFunc(desc, language)
+	}
+	return
+}
+
+// SetStickerEmojis implements tele.API.
+func (m *MockAPI) SetStickerEmojis(sticker string, emojis []string) (r0 error) {
+	m.record("SetStickerEmojis", sticker, emojis)
+	if m.SetStickerEmojisFunc != nil {
+		return m.SetStickerEmojisFunc(sticker, emojis)
+	}
+	return
+}
+
+// SetStickerKeywords implements tele.API.
+func (m *MockAPI) SetStickerKeywords(sticker string, keywords []string) (r0 error) {
+	m.record("SetStickerKeywords", sticker, keywords)
+	if m.SetStickerKeywordsFunc != nil {
+		return m.SetStickerKeywordsFunc(sticker, keywords)
+	}
+	return
+}
+
+// SetStickerMaskPosition implements tele.API.
+func (m *MockAPI) SetStickerMaskPosition(sticker string, mask tele.MaskPosition) (r0 error) {
+	m.record("SetStickerMaskPosition", sticker, mask)
+	if m.SetStickerMaskPositionFunc != nil {
+		return m.SetStickerMaskPositionFunc(sticker, mask)
+	}
+	return
+}
+
+// SetStickerPosition implements tele.API.
+func (m *MockAPI) SetStickerPosition(sticker string, position int) (r0 error) {
+	m.record("SetStickerPosition", sticker, position)
+	if m.SetStickerPositionFunc != nil {
+		return m.SetStickerPositionFunc(sticker, position)
+	}
+	return
+}
+
+// SetStickerSetThumb implements tele.API.
+func (m *MockAPI) SetStickerSetThumb(of tele.Recipient, set *tele.StickerSet) (r0 error) {
+	m.record("SetStickerSetThumb", of, set)
+	if m.SetStickerSetThumbFunc != nil {
+		return m.SetStickerSetThumbFunc(of, set)
+	}
+	return
+}
+
+// SetStickerSetTitle implements tele.API.
+func (m *MockAPI) SetStickerSetTitle(s tele.StickerSet) (r0 error) {
+	m.record("SetStickerSetTitle", s)
+	if m.SetStickerSetTitleFunc != nil {
+		return m.SetStickerSetTitleFunc(s)
+	}
+	return
+}
+
+// SetUserEmojiStatus implements tele.API.
+func (m *MockAPI) SetUserEmojiStatus(user tele.Recipient, emojiStatusCustomEmojiID string, expirationDate ...int64) (r0 error) {
+	m.record("SetUserEmojiStatus", user, emojiStatusCustomEmojiID, expirationDate)
+	if m.SetUserEmojiStatusFunc != nil {
+		return m.SetUserEmojiStatusFunc(user, emojiStatusCustomEmojiID, expirationDate...)
+	}
+	return
+}
+
+// SetWebhook implements tele.API.
+func (m *MockAPI) SetWebhook(w *tele.Webhook) (r0 error) {
+	m.record("SetWebhook", w)
+	if m.SetWebhookFunc != nil {
+		return m.SetWebhookFunc(w)
+	}
+	return
+}
+
+// Ship implements tele.API.
+func (m *MockAPI) Ship(query *tele.ShippingQuery, what ...interface{}) (r0 error) {
+	m.record("Ship", query, what)
+	if m.ShipFunc != nil {
+		return m.ShipFunc(query, what...)
+	}
+	return
+}
+
+// StarTransactions implements tele.API.
+func (m *MockAPI) StarTransactions(offset int, limit int) (r0 []tele.StarTransaction, r1 error) {
+	m.record("StarTransactions", offset, limit)
+	if m.StarTransactionsFunc != nil {
+		return m.StarTransactionsFunc(offset, limit)
+	}
+	return
+}
+
+// StickerSet implements tele.API.
+func (m *MockAPI) StickerSet(name string) (r0 *tele.StickerSet, r1 error) {
+	m.record("StickerSet", name)
+	if m.StickerSetFunc != nil {
+		return m.StickerSetFunc(name)
+	}
+	return
+}
+
+// StopLiveLocation implements tele.API.
+func (m *MockAPI) StopLiveLocation(msg tele.Editable, opts ...interface{}) (r0 *tele.Message, r1 error) {
+	m.record("StopLiveLocation", msg, opts)
+	if m.StopLiveLocationFunc != nil {
+		return m.StopLiveLocationFunc(msg, opts...)
+	}
+	return
+}
+
+// StopPoll implements tele.API.
+func (m *MockAPI) StopPoll(msg tele.Editable, opts ...interface{}) (r0 *tele.Poll, r1 error) {
+	m.record("StopPoll", msg, opts)
+	if m.StopPollFunc != nil {
+		return m.StopPollFunc(msg, opts...)
+	}
+	return
+}
+
+// TopicIconStickers implements tele.API.
+func (m *MockAPI) TopicIconStickers() (r0 []tele.Sticker, r1 error) {
+	m.record("TopicIconStickers")
+	if m.TopicIconStickersFunc != nil {
+		return m.TopicIconStickersFunc()
+	}
+	return
+}
+
+// Unban implements tele.API.
+func (m *MockAPI) Unban(chat *tele.Chat, user *tele.User, forBanned ...bool) (r0 error) {
+	m.record("Unban", chat, user, forBanned)
+	if m.UnbanFunc != nil {
+		return m.UnbanFunc(chat, user, forBanned...)
+	}
+	return
+}
+
+// UnbanSenderChat implements tele.API.
+func (m *MockAPI) UnbanSenderChat(chat *tele.Chat, sender tele.Recipient) (r0 error) {
+	m.record("UnbanSenderChat", chat, sender)
+	if m.UnbanSenderChatFunc != nil {
+		return m.UnbanSenderChatFunc(chat, sender)
+	}
+	return
+}
+
+// UnhideGeneralTopic implements tele.API.
+func (m *MockAPI) UnhideGeneralTopic(chat *tele.Chat) (r0 error) {
+	m.record("UnhideGeneralTopic", chat)
+	if m.UnhideGeneralTopicFunc != nil {
+		return m.UnhideGeneralTopicFunc(chat)
+	}
+	return
+}
+
+// Unpin implements tele.API.
+func (m *MockAPI) Unpin(chat tele.Recipient, messageID ...int) (r0 error) {
+	m.record("Unpin", chat, messageID)
+	if m.UnpinFunc != nil {
+		return m.UnpinFunc(chat, messageID...)
+	}
+	return
+}
+
+// UnpinAll implements tele.API.
+func (m *MockAPI) UnpinAll(chat tele.Recipient) (r0 error) {
+	m.record("UnpinAll", chat)
+	if m.UnpinAllFunc != nil {
+		return m.UnpinAllFunc(chat)
+	}
+	return
+}
+
+// UnpinAllGeneralTopicMessages implements tele.API.
+func (m *MockAPI) UnpinAllGeneralTopicMessages(chat *tele.Chat) (r0 error) {
+	m.record("UnpinAllGeneralTopicMessages", chat)
+	if m.UnpinAllGeneralTopicMessagesFunc != nil {
+		return m.UnpinAllGeneralTopicMessagesFunc(chat)
+	}
+	return
+}
+
+// UnpinAllTopicMessages implements tele.API.
+func (m *MockAPI) UnpinAllTopicMessages(chat *tele.Chat, topic *tele.Topic) (r0 error) {
+	m.record("UnpinAllTopicMessages", chat, topic)
+	if m.UnpinAllTopicMessagesFunc != nil {
+		return m.UnpinAllTopicMessagesFunc(chat, topic)
+	}
+	return
+}
+
+// UploadSticker implements tele.API.
+func (m *MockAPI) UploadSticker(to tele.Recipient, format tele.StickerSetFormat, f tele.File) (r0 *tele.File, r1 error) {
+	m.record("UploadSticker", to, format, f)
+	if m.UploadStickerFunc != nil {
+		return m.UploadStickerFunc(to, format, f)
+	}
+	return
+}
+
+// UserBoosts implements tele.API.
+func (m *MockAPI) UserBoosts(chat tele.Recipient, user tele.Recipient) (r0 []tele.Boost, r1 error) {
+	m.record("UserBoosts", chat, user)
+	if m.UserBoostsFunc != nil {
+		return m.UserBoostsFunc(chat, user)
+	}
+	return
+}
+
+// VerifyChat implements tele.API.
+func (m *MockAPI) VerifyChat(chat *tele.Chat, level tele.VerificationLevel, requirements *tele.VerificationRequirements) (r0 error) {
+	m.record("VerifyChat", chat, level, requirements)
+	if m.VerifyChatFunc != nil {
+		return m.VerifyChatFunc(chat, level, requirements)
+	}
+	return
+}
+
+// VerifyUser implements tele.API.
+func (m *MockAPI) VerifyUser(user tele.Recipient, level tele.VerificationLevel, requirements *tele.VerificationRequirements) (r0 error) {
+	m.record("VerifyUser", user, level, requirements)
+	if m.VerifyUserFunc != nil {
+		return m.VerifyUserFunc(user, level, requirements)
+	}
+	return
+}
+
+// Webhook implements tele.API.
+func (m *MockAPI) Webhook() (r0 *tele.Webhook, r1 error) {
+	m.record("Webhook")
+	if m.WebhookFunc != nil {
+		return m.WebhookFunc()
+	}
+	return
+}
@@ -0,0 +1,40 @@
+package telebottest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	tele "github.com/irijopa/telebot"
+)
+
+func TestMockAPI(t *testing.T) {
+	api := &MockAPI{}
+
+	var sent []interface{}
+	api.SendFunc = func(to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error) {
+		sent = append(sent, what)
+		return &tele.Message{ID: 1}, nil
+	}
+
+	msg := &tele.Message{Chat: &tele.Chat{ID: 1}, Sender: &tele.User{ID: 1}, Text: "hi"}
+	c := tele.NewContext(api, tele.Update{Message: msg})
+
+	assert.NoError(t, c.Send("hello"))
+	assert.Equal(t, []interface{}{"hello"}, sent)
+
+	// unset methods return zero values
+	assert.NoError(t, c.Delete())
+
+	calls := api.CallsOf("Send")
+	if assert.Len(t, calls, 1) {
+		assert.Equal(t, "hello", calls[0].Args[1])
+	}
+	assert.Len(t, api.Calls(), 2)
+
+	api.VerifyUserFunc = func(tele.Recipient, tele.VerificationLevel, *tele.VerificationRequirements) error {
+		return errors.New("verification failed")
+	}
+	assert.Error(t, c.Bot().VerifyUser(msg.Sender, "", nil))
+}
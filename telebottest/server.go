@@ -0,0 +1,412 @@
+// Package telebottest provides an in-process fake of the Bot API server,
+// so bots can be tested end to end without network access.
+//
+// The server keeps the state of chats and messages, answers the methods
+// telebot calls, records every request and delivers the injected
+// updates through its poller.
+//
+// Example:
+//
+//	func TestStart(t *testing.T) {
+//		srv := telebottest.NewServer()
+//		defer srv.Close()
+//
+//		b, err := tele.NewBot(srv.Settings())
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//
+//		b.Handle("/start", func(c tele.Context) error {
+//			return c.Send("Hello world!")
+//		})
+//
+//		go b.Start()
+//		defer b.Stop()
+//
+//		srv.Text(&tele.Chat{ID: 1}, &tele.User{ID: 1}, "/start")
+//
+//		req, err := srv.Wait("sendMessage")
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		if req.Params["text"] != "Hello world!" {
+//			t.Errorf("unexpected reply: %s", req.Params["text"])
+//		}
+//	}
+package telebottest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tele "github.com/irijopa/telebot"
+)
+
+//go:generate go run ./internal/mockgen -src ../api.go -out mock.go
+
+// DefaultToken is the token the server accepts by default.
+const DefaultToken = "123456:TEST"
+
+// DefaultTimeout is how long Wait waits for the request by default.
+const DefaultTimeout = 5 * time.Second
+
+type (
+	// Request is a request made by the bot to the server.
+	Request struct {
+		Method string
+		Params map[string]string
+		Files  map[string]Upload
+
+		ctx context.Context
+	}
+
+	// Upload is a file uploaded within the request.
+	Upload struct {
+		Name string
+		MIME string
+		Data []byte
+	}
+
+	// HandlerFunc answers the request with the result, which is
+	// encoded to JSON. Return *tele.Error to answer with an API error.
+	HandlerFunc func(r *Request) (interface{}, error)
+)
+
+// Server is a fake Bot API server.
+type Server struct {
+	// URL is the base URL of the server, pass it to tele.Settings.
+	URL string
+
+	// Token is the bot token accepted by the server.
+	Token string
+
+	// Me is the bot user returned by getMe.
+	Me tele.User
+
+	// Timeout is how long Wait waits for the request,
+	// defaulted to DefaultTimeout.
+	Timeout time.Duration
+
+	srv     *httptest.Server
+	updates chan tele.Update
+
+	mu       sync.Mutex
+	changed  chan struct{}
+	requests []*Request
+	waited   map[string]int
+	handlers map[string]HandlerFunc
+	chats    map[int64]*tele.Chat
+	messages map[int64]map[int]*tele.Message
+	files    map[string]*file
+
+	lastUpdate   int
+	lastMessage  int
+	lastFile     int
+	lastCallback int
+}
+
+type file struct {
+	tele.File
+	data []byte
+}
+
+// NewServer starts a new fake server.
+func NewServer() *Server {
+	s := &Server{
+		Token: DefaultToken,
+		Me: tele.User{
+			ID:        123456,
+			FirstName: "Test",
+			Username:  "test_bot",
+			IsBot:     true,
+		},
+
+		updates:  make(chan tele.Update, 100),
+		changed:  make(chan struct{}),
+		waited:   make(map[string]int),
+		handlers: make(map[string]HandlerFunc),
+		chats:    make(map[int64]*tele.Chat),
+		messages: make(map[int64]map[int]*tele.Message),
+		files:    make(map[string]*file),
+	}
+
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Settings returns the bot settings pointing to the server
+// and receiving the updates through its poller.
+func (s *Server) Settings() tele.Settings {
+	return tele.Settings{
+		URL:    s.URL,
+		Token:  s.Token,
+		Poller: s.Poller(),
+	}
+}
+
+// Handle overrides the answer to the method.
+func (s *Server) Handle(method string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = h
+}
+
+// Requests returns all the requests made so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reqs := make([]Request, len(s.requests))
+	for i, r := range s.requests {
+		reqs[i] = *r
+	}
+	return reqs
+}
+
+// Wait returns the next request of the method, which hasn't been returned
+// by Wait yet, waiting for it to be handled for the Timeout.
+func (s *Server) Wait(method string) (*Request, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	deadline := time.After(timeout)
+
+	for {
+		s.mu.Lock()
+		var seen int
+		for _, r := range s.requests {
+			if r.Method != method {
+				continue
+			}
+			if seen == s.waited[method] {
+				s.waited[method]++
+				s.mu.Unlock()
+				return r, nil
+			}
+			seen++
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return nil, fmt.Errorf("telebottest: no %s request within %v", method, timeout)
+		}
+	}
+}
+
+// Chat returns the chat known to the server.
+func (s *Server) Chat(id int64) *tele.Chat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if chat, ok := s.chats[id]; ok {
+		c := *chat
+		return &c
+	}
+	return nil
+}
+
+// AddChat makes the chat known to the server,
+// so it's returned by getChat.
+func (s *Server) AddChat(chat *tele.Chat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := *chat
+	s.chats[chat.ID] = &c
+}
+
+// Messages returns the messages of the chat ordered by ID,
+// both sent by the bot and injected as updates.
+func (s *Server) Messages(chatID int64) []tele.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msgs := make([]tele.Message, 0, len(s.messages[chatID]))
+	for _, m := range s.messages[chatID] {
+		msgs = append(msgs, *m)
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].ID < msgs[j].ID })
+	return msgs
+}
+
+// LastMessage returns the last message of the chat, if any.
+func (s *Server) LastMessage(chatID int64) *tele.Message {
+	msgs := s.Messages(chatID)
+	if len(msgs) == 0 {
+		return nil
+	}
+	return &msgs[len(msgs)-1]
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+
+	if rest := strings.TrimPrefix(path, "file/bot"); rest != path {
+		s.serveFile(w, rest)
+		return
+	}
+
+	rest := strings.TrimPrefix(path, "bot")
+	i := strings.LastIndex(rest, "/")
+	if rest == path || i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	if rest[:i] != s.Token {
+		writeResult(w, nil, tele.ErrUnauthorized)
+		return
+	}
+
+	req, err := parseRequest(rest[i+1:], r)
+	if err != nil {
+		writeResult(w, nil, &tele.Error{Code: 400, Description: "Bad Request: " + err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	h, ok := s.handlers[req.Method]
+	s.mu.Unlock()
+
+	if !ok {
+		h = s.builtin(req.Method)
+	}
+
+	req.ctx = r.Context()
+	result, err := h(req)
+
+	// record once handled, so the state is up to date for Wait
+	s.record(req)
+	writeResult(w, result, err)
+}
+
+func (s *Server) record(req *Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, rest string) {
+	i := strings.Index(rest, "/")
+	if i < 0 || rest[:i] != s.Token {
+		http.NotFound(w, nil)
+		return
+	}
+
+	s.mu.Lock()
+	var data []byte
+	for _, f := range s.files {
+		if f.FilePath == rest[i+1:] {
+			data = f.data
+		}
+	}
+	s.mu.Unlock()
+
+	if data == nil {
+		http.NotFound(w, nil)
+		return
+	}
+	w.Write(data)
+}
+
+func parseRequest(method string, r *http.Request) (*Request, error) {
+	req := &Request{
+		Method: method,
+		Params: make(map[string]string),
+		Files:  make(map[string]Upload),
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(64 << 20); err != nil {
+			return nil, err
+		}
+		for k, v := range r.MultipartForm.Value {
+			if len(v) > 0 {
+				req.Params[k] = v[0]
+			}
+		}
+		for k, v := range r.MultipartForm.File {
+			if len(v) == 0 {
+				continue
+			}
+			f, err := v[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			data, err := ioutil.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Files[k] = Upload{
+				Name: v[0].Filename,
+				MIME: v[0].Header.Get("Content-Type"),
+				Data: data,
+			}
+		}
+		return req, nil
+	}
+
+	var params map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil && err != io.EOF {
+		return nil, err
+	}
+	for k, v := range params {
+		if str, ok := v.(string); ok {
+			req.Params[k] = str
+		} else if v != nil {
+			data, _ := json.Marshal(v)
+			req.Params[k] = string(data)
+		}
+	}
+	return req, nil
+}
+
+func writeResult(w http.ResponseWriter, result interface{}, err error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		var apiErr *tele.Error
+		if !errors.As(err, &apiErr) {
+			apiErr = &tele.Error{Code: 400, Description: "Bad Request: " + err.Error()}
+		}
+		w.WriteHeader(apiErr.Code)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"ok":          false,
+			"error_code":  apiErr.Code,
+			"description": apiErr.Description,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":     true,
+		"result": result,
+	})
+}
@@ -0,0 +1,197 @@
+package telebottest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tele "github.com/irijopa/telebot"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	b, err := tele.NewBot(srv.Settings())
+	require.NoError(t, err)
+	assert.Equal(t, "test_bot", b.Me.Username)
+
+	var (
+		menu = &tele.ReplyMarkup{}
+		btn  = menu.Data("Count", "count")
+	)
+	menu.Inline(menu.Row(btn))
+
+	var count int
+	// messages are routed to OnAny only, see ProcessContext
+	b.Handle(tele.OnAny, func(c tele.Context) error {
+		return c.Send("Count: 0", menu)
+	})
+	b.Handle(&btn, func(c tele.Context) error {
+		count++
+		if err := c.Edit("Count: "+string(rune('0'+count)), menu); err != nil {
+			return err
+		}
+		return c.Respond()
+	})
+
+	go b.Start()
+	defer b.Stop()
+
+	chat := &tele.Chat{ID: 1, Type: tele.ChatPrivate}
+	user := &tele.User{ID: 1, FirstName: "User"}
+
+	srv.Text(chat, user, "/start")
+
+	req, err := srv.Wait("sendMessage")
+	require.NoError(t, err)
+	assert.Equal(t, "Count: 0", req.Params["text"])
+
+	msg := srv.LastMessage(chat.ID)
+	require.NotNil(t, msg)
+	assert.Equal(t, "Count: 0", msg.Text)
+	require.NotNil(t, msg.ReplyMarkup)
+
+	_, err = srv.Press(msg, user, "Count")
+	require.NoError(t, err)
+
+	_, err = srv.Wait("answerCallbackQuery")
+	require.NoError(t, err)
+	assert.Equal(t, "Count: 1", srv.LastMessage(chat.ID).Text)
+	assert.NotZero(t, srv.LastMessage(chat.ID).LastEdit)
+
+	// both the user's and the bot's messages are kept
+	assert.Len(t, srv.Messages(chat.ID), 2)
+
+	_, err = srv.Press(msg, user, "Missing")
+	assert.Error(t, err)
+
+	// the message without a chat is sent in private
+	sent := srv.Message(&tele.Message{Sender: &tele.User{ID: 2}, Text: "/start"})
+	assert.Equal(t, int64(2), sent.Chat.ID)
+
+	_, err = srv.Wait("sendMessage")
+	require.NoError(t, err)
+	assert.NotNil(t, srv.LastMessage(2))
+}
+
+func TestServer_Methods(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	b, err := tele.NewBot(srv.Settings())
+	require.NoError(t, err)
+
+	chat := &tele.Chat{ID: 1}
+
+	msg, err := b.Send(chat, &tele.Document{File: tele.FromBytes("a.txt", []byte("data"))})
+	require.NoError(t, err)
+	require.NotNil(t, msg.Document)
+	assert.Equal(t, "a.txt", msg.Document.FileName)
+
+	req, err := srv.Wait("sendDocument")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), req.Files["document"].Data)
+
+	var buf bytes.Buffer
+	require.NoError(t, b.DownloadTo(&msg.Document.File, &buf))
+	assert.Equal(t, "data", buf.String())
+
+	// the same file is sent by its ID
+	_, err = b.Send(chat, &tele.Document{File: tele.File{FileID: msg.Document.FileID}})
+	require.NoError(t, err)
+
+	_, err = b.Send(chat, &tele.Document{File: tele.File{FileID: "missing"}})
+	assert.ErrorIs(t, err, tele.ErrWrongFileID)
+
+	text, err := b.Send(chat, "text")
+	require.NoError(t, err)
+
+	_, err = b.Edit(text, "text")
+	assert.ErrorIs(t, err, tele.ErrSameMessageContent)
+
+	_, err = b.Edit(text, "edited")
+	require.NoError(t, err)
+
+	_, err = b.Raw("sendMessage", map[string]string{"text": "text"})
+	assert.ErrorIs(t, err, tele.ErrEmptyChatID)
+
+	require.NoError(t, b.Delete(text))
+	assert.ErrorIs(t, b.Delete(text), tele.ErrNotFoundToDelete)
+
+	loc, err := b.Send(chat, &tele.Location{Lat: 1.5, Lng: 2.5})
+	require.NoError(t, err)
+	require.NotNil(t, loc.Location)
+	assert.Equal(t, float32(1.5), loc.Location.Lat)
+
+	venue, err := b.Send(chat, &tele.Venue{Location: tele.Location{Lat: 1, Lng: 2}, Title: "Title"})
+	require.NoError(t, err)
+	require.NotNil(t, venue.Venue)
+	assert.Equal(t, "Title", venue.Venue.Title)
+
+	contact, err := b.Raw("sendContact", map[string]string{"chat_id": "1", "phone_number": "123", "first_name": "Name"})
+	require.NoError(t, err)
+	assert.Contains(t, string(contact), `"phone_number":"123"`)
+
+	dice, err := b.Send(chat, tele.Cube)
+	require.NoError(t, err)
+	require.NotNil(t, dice.Dice)
+
+	poll, err := b.Send(chat, &tele.Poll{
+		Type:     tele.PollRegular,
+		Question: "Question",
+		Options:  []tele.PollOption{{Text: "A"}, {Text: "B"}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, poll.Poll)
+	assert.Equal(t, "Question", poll.Poll.Question)
+	assert.Len(t, poll.Poll.Options, 2)
+
+	require.NoError(t, b.Notify(chat, tele.Typing))
+
+	_, err = b.Raw("unknownMethod", nil)
+	assert.ErrorIs(t, err, tele.ErrNotFound)
+
+	srv.Handle("getChat", func(r *Request) (interface{}, error) {
+		return tele.Chat{ID: 1, Title: "Overridden"}, nil
+	})
+	c, err := b.ChatByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Overridden", c.Title)
+
+	srv.Token = "other"
+	_, err = b.Send(chat, "text")
+	assert.ErrorIs(t, err, tele.ErrUnauthorized)
+}
+
+func TestServer_LongPoller(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	pref := srv.Settings()
+	pref.Poller = &tele.LongPoller{Timeout: 100 * time.Millisecond}
+
+	b, err := tele.NewBot(pref)
+	require.NoError(t, err)
+
+	got := make(chan string, 1)
+	b.Handle(tele.OnAny, func(c tele.Context) error {
+		got <- c.Text()
+		return nil
+	})
+
+	go b.Start()
+	defer b.Stop()
+
+	srv.Text(&tele.Chat{ID: 1}, &tele.User{ID: 1}, "hello")
+
+	select {
+	case text := <-got:
+		assert.Equal(t, "hello", text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("update is not delivered")
+	}
+}
@@ -0,0 +1,136 @@
+package telebottest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tele "github.com/irijopa/telebot"
+)
+
+// Tracer is a tele.Tracer recording the spans in memory:
+//
+//	tracer := telebottest.NewTracer()
+//	pref := srv.Settings()
+//	pref.Tracer = tracer
+//
+//	// ...
+//
+//	for _, span := range tracer.Spans() {
+//		fmt.Println(span.Name, span.Parent, span.Duration())
+//	}
+type Tracer struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// Span is a recorded span. The IDs start from 1, and
+// Parent is 0 for the spans started without a parent.
+type Span struct {
+	ID     int
+	Parent int
+	Name   string
+	Attrs  map[string]interface{}
+	Err    error
+	Start  time.Time
+	End    time.Time
+
+	tracer *Tracer
+}
+
+// NewTracer returns an empty tracer.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+type spanKey struct{}
+
+// Start implements tele.Tracer.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, tele.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &Span{
+		ID:     len(t.spans) + 1,
+		Name:   name,
+		Attrs:  make(map[string]interface{}),
+		Start:  time.Now(),
+		tracer: t,
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent.tracer == t {
+		s.Parent = parent.ID
+	}
+	s.setAttributes(attrs)
+
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, spanKey{}, s), spanHandle{s}
+}
+
+// Spans returns the copies of the spans in the order they were started.
+func (t *Tracer) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]Span, len(t.spans))
+	for i, s := range t.spans {
+		spans[i] = *s
+		spans[i].Attrs = make(map[string]interface{}, len(s.Attrs))
+		for k, v := range s.Attrs {
+			spans[i].Attrs[k] = v
+		}
+	}
+	return spans
+}
+
+// Children returns the spans started as the children of the span.
+func (t *Tracer) Children(parent Span) (children []Span) {
+	for _, s := range t.Spans() {
+		if s.Parent == parent.ID {
+			children = append(children, s)
+		}
+	}
+	return children
+}
+
+// Ended reports whether the span is finished.
+func (s Span) Ended() bool {
+	return !s.End.IsZero()
+}
+
+// Duration returns the duration of the finished span.
+func (s Span) Duration() time.Duration {
+	if !s.Ended() {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
+// setAttributes adds the key-value pairs, t.mu must be held.
+func (s *Span) setAttributes(attrs []interface{}) {
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if key, ok := attrs[i].(string); ok {
+			s.Attrs[key] = attrs[i+1]
+		}
+	}
+}
+
+// spanHandle is the tele.Span of the recorded span. It's separate from
+// Span, since the fields of Span are named after its methods.
+type spanHandle struct {
+	s *Span
+}
+
+func (h spanHandle) SetAttributes(attrs ...interface{}) {
+	h.s.tracer.mu.Lock()
+	h.s.setAttributes(attrs)
+	h.s.tracer.mu.Unlock()
+}
+
+func (h spanHandle) End(err error) {
+	h.s.tracer.mu.Lock()
+	if h.s.End.IsZero() {
+		h.s.Err = err
+		h.s.End = time.Now()
+	}
+	h.s.tracer.mu.Unlock()
+}
@@ -0,0 +1,66 @@
+package telebottest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tele "github.com/irijopa/telebot"
+)
+
+func TestTracer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	tracer := NewTracer()
+
+	pref := srv.Settings()
+	pref.Tracer = tracer
+	pref.Synchronous = true
+	pref.OnError = func(error, tele.Context) {}
+
+	b, err := tele.NewBot(pref)
+	require.NoError(t, err)
+
+	// messages are routed to OnAny only, see ProcessContext
+	b.Handle(tele.OnAny, func(c tele.Context) error {
+		if err := c.Send("one"); err != nil {
+			return err
+		}
+		if err := c.Send("two"); err != nil {
+			return err
+		}
+		return errors.New("oops")
+	})
+
+	chat := &tele.Chat{ID: 1, Type: tele.ChatPrivate}
+	b.ProcessUpdate(tele.Update{ID: 1, Message: &tele.Message{Chat: chat, Text: "hi"}})
+
+	spans := tracer.Spans()
+	require.Len(t, spans, 4) // getMe, update, two requests
+
+	assert.Equal(t, tele.SpanAPI, spans[0].Name)
+	assert.Equal(t, "getMe", spans[0].Attrs["method"])
+	assert.Zero(t, spans[0].Parent)
+
+	root := spans[1]
+	assert.Equal(t, tele.SpanUpdate, root.Name)
+	assert.Equal(t, 1, root.Attrs["update_id"])
+	assert.Equal(t, int64(1), root.Attrs["chat_id"])
+	assert.EqualError(t, root.Err, "oops")
+	assert.True(t, root.Ended())
+
+	children := tracer.Children(root)
+	require.Len(t, children, 2)
+	for _, span := range children {
+		assert.Equal(t, tele.SpanAPI, span.Name)
+		assert.Equal(t, "sendMessage", span.Attrs["method"])
+		assert.Equal(t, 200, span.Attrs["status"])
+		assert.NoError(t, span.Err)
+		assert.True(t, span.Ended())
+		assert.False(t, span.Start.Before(root.Start))
+		assert.False(t, span.End.After(root.End))
+	}
+}
@@ -0,0 +1,128 @@
+package telebottest
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	tele "github.com/irijopa/telebot"
+)
+
+// Poller returns a poller delivering the updates injected into the server.
+// Bots using tele.LongPoller receive them through getUpdates as well.
+func (s *Server) Poller() tele.Poller {
+	return &poller{s: s}
+}
+
+type poller struct {
+	s *Server
+}
+
+func (p *poller) Poll(b *tele.Bot, dest chan tele.Update, stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case u := <-p.s.updates:
+			dest <- u
+		}
+	}
+}
+
+// Update injects the update. Its ID is assigned if it's empty.
+func (s *Server) Update(u tele.Update) {
+	s.mu.Lock()
+	if u.ID == 0 {
+		s.lastUpdate++
+		u.ID = s.lastUpdate
+	}
+	s.mu.Unlock()
+
+	s.updates <- u
+}
+
+// Text injects the text message sent by the user to the chat.
+func (s *Server) Text(chat *tele.Chat, from *tele.User, text string) *tele.Message {
+	return s.Message(&tele.Message{
+		Chat:   chat,
+		Sender: from,
+		Text:   text,
+	})
+}
+
+// Message injects the message. Its ID and date are assigned
+// if they are empty. The message without a chat is sent
+// to the bot in private by its sender.
+func (s *Server) Message(msg *tele.Message) *tele.Message {
+	s.mu.Lock()
+	m := *msg
+	if m.Chat == nil {
+		m.Chat = &tele.Chat{Type: tele.ChatPrivate}
+		if u := m.Sender; u != nil {
+			m.Chat.ID = u.ID
+			m.Chat.FirstName = u.FirstName
+			m.Chat.LastName = u.LastName
+			m.Chat.Username = u.Username
+		}
+	}
+	if m.ID == 0 {
+		s.lastMessage++
+		m.ID = s.lastMessage
+	}
+	if m.Unixtime == 0 {
+		m.Unixtime = time.Now().Unix()
+	}
+	if _, ok := s.chats[m.Chat.ID]; !ok {
+		chat := *m.Chat
+		s.chats[chat.ID] = &chat
+	}
+	s.storeMessage(&m)
+	sent := m
+	s.mu.Unlock()
+
+	s.Update(tele.Update{Message: &sent})
+	return &sent
+}
+
+// Click injects the callback query of the user pressing
+// the inline button with the given callback data.
+func (s *Server) Click(msg *tele.Message, from *tele.User, data string) *tele.Callback {
+	s.mu.Lock()
+	s.lastCallback++
+	cb := &tele.Callback{
+		ID:      strconv.Itoa(s.lastCallback),
+		Sender:  from,
+		Message: msg,
+		Data:    data,
+	}
+	s.mu.Unlock()
+
+	s.Update(tele.Update{Callback: cb})
+	return cb
+}
+
+// Press looks up the inline button of the message by its text
+// and clicks it. The message is taken in its current state.
+func (s *Server) Press(msg *tele.Message, from *tele.User, text string) (*tele.Callback, error) {
+	s.mu.Lock()
+	current, ok := s.messages[msg.Chat.ID][msg.ID]
+	var m tele.Message
+	if ok {
+		m = *current
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, errors.New("telebottest: message not found")
+	}
+	if m.ReplyMarkup != nil {
+		for _, row := range m.ReplyMarkup.InlineKeyboard {
+			for _, btn := range row {
+				if btn.Text == text {
+					return s.Click(&m, from, btn.Data), nil
+				}
+			}
+		}
+	}
+	return nil, errors.New("telebottest: button " + strconv.Quote(text) + " not found")
+}
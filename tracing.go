@@ -0,0 +1,92 @@
+package telebot
+
+import "context"
+
+// Tracer starts the spans of the bot. It's set by Settings.Tracer,
+// so the bot works with any tracing backend. See telebottest.Tracer
+// for the one recording the spans in memory.
+//
+// A span is started for every handled update, covering the middleware
+// and the handler, and for every Bot API request made meanwhile.
+// The span context travels through the Context, see ContextCarrier,
+// so the requests made through it become the children of the update span.
+type Tracer interface {
+	// Start starts a span as a child of the span carried by ctx, if any,
+	// and returns the context carrying the new span. The attributes go
+	// in key-value pairs, like the fields of Logger.
+	Start(ctx context.Context, name string, attrs ...interface{}) (context.Context, Span)
+}
+
+// Span is a single traced operation.
+type Span interface {
+	// SetAttributes adds the key-value pairs to the span.
+	SetAttributes(attrs ...interface{})
+
+	// End finishes the span with the error of the operation, if any.
+	End(err error)
+}
+
+// Span names used by the bot.
+const (
+	SpanUpdate = "telebot.update"
+	SpanAPI    = "telebot.api"
+)
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, _ string, _ ...interface{}) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...interface{}) {}
+func (nopSpan) End(error)                    {}
+
+// tracing reports whether the bot has a tracer set.
+func (b *Bot) tracing() bool {
+	_, nop := b.tracer.(nopTracer)
+	return b.tracer != nil && !nop
+}
+
+// context returns the context of the bot. The bots bound to the
+// updates carry the span of the update.
+func (b *Bot) context() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
+// root returns the bot the bound one is made of.
+func (b *Bot) root() *Bot {
+	if b.parent != nil {
+		return b.parent
+	}
+	return b
+}
+
+// withContext returns a copy of the bot, which requests are made
+// within ctx. The configuration and state are shared with the original.
+func (b *Bot) withContext(ctx context.Context) *Bot {
+	root := b.root()
+	return &Bot{
+		Me:      root.Me,
+		Token:   root.Token,
+		URL:     root.URL,
+		Updates: root.Updates,
+		Poller:  root.Poller,
+		config:  root.config,
+
+		ctx:    ctx,
+		parent: root,
+	}
+}
+
+// startSpan starts the span within the context of the bot.
+func (b *Bot) startSpan(name string, attrs ...interface{}) (context.Context, Span) {
+	if b.tracer == nil {
+		return b.context(), nopSpan{}
+	}
+	return b.tracer.Start(b.context(), name, attrs...)
+}
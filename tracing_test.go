@@ -0,0 +1,59 @@
+package telebot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotWithContext(t *testing.T) {
+	b, err := NewBot(Settings{Offline: true, FileCache: NewMemoryFileCache()})
+	require.NoError(t, err)
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+	bound := b.withContext(ctx).withContext(ctx)
+
+	assert.Equal(t, ctx, bound.context())
+	assert.Same(t, b, bound.root())
+	assert.Equal(t, context.Background(), b.context())
+	assert.Same(t, b.client, bound.client)
+	assert.Equal(t, b.fileCache, bound.fileCache)
+
+	// the context keeps the registered bot, the requests are bound
+	c := b.NewContext(Update{}).(*nativeContext)
+	c.SetContext(ctx)
+	assert.Same(t, b, c.Bot())
+	assert.Equal(t, ctx, c.api().(*Bot).context())
+}
+
+func TestBotZero(t *testing.T) {
+	var b Bot
+
+	var handled bool
+	// messages are routed to OnAny only, see ProcessContext
+	b.handlers = map[string]HandlerFunc{OnAny: func(c Context) error {
+		handled = true
+		return nil
+	}}
+	b.synchronous = true
+
+	assert.NotPanics(t, func() {
+		b.ProcessUpdate(Update{Message: &Message{Text: "text", Chat: &Chat{}}})
+	})
+	assert.True(t, handled)
+	assert.Zero(t, b.Duplicates())
+
+	// the webhook reports to the metrics of the bot
+	h := &Webhook{}
+	h.attach(&b, make(chan Update, 1))
+	assert.NotPanics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"update_id":1}`)))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
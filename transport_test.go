@@ -0,0 +1,88 @@
+package telebot
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	me := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true,"result":{"id":1,"is_bot":true,"username":"bot"}}`))
+	}
+
+	t.Run("proxy", func(t *testing.T) {
+		var host string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host = r.Host
+			me(w, r)
+		}))
+		defer proxy.Close()
+
+		b, err := NewBot(Settings{
+			URL:       "http://api.telegram.test",
+			Transport: &Transport{Proxy: proxy.URL},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "bot", b.Me.Username)
+		assert.Equal(t, "api.telegram.test", host)
+
+		_, err = (&Transport{Proxy: "ftp://proxy"}).Client()
+		assert.Error(t, err)
+	})
+
+	t.Run("ca_file", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(me))
+		defer srv.Close()
+
+		ca := filepath.Join(t.TempDir(), "ca.pem")
+		cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+		require.NoError(t, os.WriteFile(ca, cert, 0600))
+
+		_, err := NewBot(Settings{URL: srv.URL, Transport: &Transport{}})
+		assert.Error(t, err)
+
+		_, err = NewBot(Settings{URL: srv.URL, Transport: &Transport{CAFile: ca}})
+		assert.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(ca, []byte("garbage"), 0600))
+		_, err = NewBot(Settings{URL: srv.URL, Transport: &Transport{CAFile: ca}})
+		assert.Error(t, err)
+	})
+
+	t.Run("timeouts", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(200 * time.Millisecond):
+			}
+		}))
+		defer srv.Close()
+
+		b, err := NewBot(Settings{
+			URL:       srv.URL,
+			Offline:   true,
+			Transport: &Transport{Timeout: 50 * time.Millisecond},
+		})
+		require.NoError(t, err)
+
+		_, err = b.Raw("sendMessage", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		tr := &Transport{PollTimeout: time.Minute, UploadTimeout: time.Hour}
+		assert.Equal(t, DefaultTimeout, tr.timeout("sendMessage", false))
+		assert.Equal(t, time.Minute, tr.timeout("getUpdates", false))
+		assert.Equal(t, time.Hour, tr.timeout("sendPhoto", true))
+	})
+
+	_, err := NewBot(Settings{Offline: true, Client: http.DefaultClient, Transport: &Transport{}})
+	assert.Error(t, err)
+}
@@ -1,6 +1,10 @@
 package telebot
 
-import "strings"
+import (
+	"context"
+	"strings"
+	"time"
+)
 
 // Update object represents an incoming update.
 type Update struct {
@@ -29,12 +33,32 @@ type Update struct {
 	EditedBusinessMessage   *Message                 `json:"edited_business_message"`
 	DeletedBusinessMessages *BusinessMessagesDeleted `json:"deleted_business_messages"`
 	PurchasedPaidMedia      *PaidMediaPurchased      `json:"purchased_paid_media"`
+
+	// reply is set by the webhook in the reply mode.
+	reply *webhookReply
+
+	// raw is the JSON the update is decoded from, kept
+	// only while the updates are recorded, see Recorder.Poller.
+	raw string
+}
+
+// Kind returns the type of the update as it's named
+// in Bot API, e.g. message or callback_query.
+func (u Update) Kind() string {
+	return updateKind(u)
 }
 
 // ProcessUpdate processes a single incoming update.
 // A started bot calls this function automatically.
 func (b *Bot) ProcessUpdate(u Update) {
+	if b.duplicate(u) {
+		u.reply.release()
+		return
+	}
+	b.meter().UpdateReceived(updateKind(u))
 	b.ProcessContext(b.NewContext(u))
+	// no handler is going to use the webhook reply
+	u.reply.releaseIdle()
 }
 
 // ProcessContext processes the given context.
@@ -286,7 +310,7 @@ func (b *Bot) ProcessContext(c Context) {
 				if handler, ok := b.handlers["\f"+unique]; ok {
 					u.Callback.Unique = unique
 					u.Callback.Data = payload
-					b.runHandler(handler, c)
+					b.runHandler(b.measure("\f"+unique, handler), c)
 					return
 				}
 			}
@@ -371,7 +395,7 @@ func (b *Bot) ProcessContext(c Context) {
 
 func (b *Bot) handle(end string, c Context) bool {
 	if handler, ok := b.handlers[end]; ok {
-		b.runHandler(handler, c)
+		b.runHandler(b.measure(end, handler), c)
 		return true
 	}
 	return false
@@ -411,11 +435,34 @@ func (b *Bot) handleMedia(c Context) bool {
 	return true
 }
 
+// measure reports the latency and the error of the handler to the metrics.
+func (b *Bot) measure(end string, h HandlerFunc) HandlerFunc {
+	return func(c Context) error {
+		start := time.Now()
+		err := h(c)
+		b.meter().HandlerDone(end, time.Since(start), err)
+		return err
+	}
+}
+
 func (b *Bot) runHandler(h HandlerFunc, c Context) {
+	c.Update().reply.hold()
 	f := func() {
-		if err := h(c); err != nil {
+		span := Span(nopSpan{})
+		if cc, ok := c.(ContextCarrier); ok && b.tracing() {
+			var ctx context.Context
+			ctx, span = b.tracer.Start(cc.Context(), SpanUpdate, logFields(c)...)
+			cc.SetContext(ctx)
+		}
+
+		err := h(c)
+		span.End(err)
+		if err != nil {
 			b.OnError(err, c)
 		}
+		// the handler didn't use the webhook reply,
+		// let the webhook respond immediately
+		c.Update().reply.release()
 	}
 	if b.synchronous {
 		f()
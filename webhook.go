@@ -2,17 +2,27 @@ package telebot
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // A WebhookTLS specifies the path to a key and a cert so the poller can open
-// a TLS listener.
+// a TLS listener. If SelfSigned is filled, the key and cert are generated
+// automatically, see WebhookSelfSigned.
 type WebhookTLS struct {
 	Key  string `json:"key"`
 	Cert string `json:"cert"`
+
+	SelfSigned *WebhookSelfSigned `json:"self_signed"`
 }
 
 // A WebhookEndpoint describes the endpoint to which telegram will send its requests.
@@ -39,7 +49,15 @@ type WebhookEndpoint struct {
 // add the Webhook to a http-mux.
 //
 // If you want to ignore the automatic setWebhook call, you can set IgnoreSetWebhook to true.
+//
+// If ReplyTimeout is set, the webhook works in the reply mode: the first Send, Reply
+// or Respond call made by the handler within the timeout is written right into the
+// HTTP response instead of a separate request to the Bot API. Telegram doesn't report
+// the result of such calls, so errors are lost and the sent message isn't returned.
 type Webhook struct {
+	// stats goes first to be 64-bit aligned for atomic operations.
+	stats WebhookStats
+
 	Listen           string   `json:"url"`
 	MaxConnections   int      `json:"max_connections"`
 	AllowedUpdates   []string `json:"allowed_updates"`
@@ -48,6 +66,22 @@ type Webhook struct {
 	SecretToken      string   `json:"secret_token"`
 	IgnoreSetWebhook bool     `json:"ignore_set_web_hook"`
 
+	// ReplyTimeout enables the reply mode, see above.
+	ReplyTimeout time.Duration `json:"reply_timeout"`
+
+	// MaxBodySize limits the size of the request body,
+	// defaulted to DefaultWebhookBodySize.
+	MaxBodySize int64 `json:"max_body_size"`
+
+	// EnqueueTimeout limits the time the update waits for a free slot in
+	// the updates channel. Once it's expired, the request is answered with
+	// 503 so Telegram retries it later. Zero means waiting forever.
+	EnqueueTimeout time.Duration `json:"enqueue_timeout"`
+
+	// CheckIP rejects requests not coming from TelegramSubnets. Make sure
+	// the remote address is preserved if the webhook is behind a proxy.
+	CheckIP bool `json:"check_ip"`
+
 	// (WebhookInfo)
 	HasCustomCert     bool   `json:"has_custom_certificate"`
 	PendingUpdates    int    `json:"pending_update_count"`
@@ -58,14 +92,23 @@ type Webhook struct {
 	TLS      *WebhookTLS
 	Endpoint *WebhookEndpoint
 
+	mu   sync.RWMutex
 	dest chan<- Update
 	bot  *Bot
+
+	certs  certStore
+	shared *certStore // of the WebhookServer, see WebhookServer.Add
 }
 
+// DefaultWebhookBodySize is the default limit of the webhook request body.
+const DefaultWebhookBodySize = 1 << 20
+
 func (h *Webhook) getFiles() map[string]File {
 	m := make(map[string]File)
 
-	if h.TLS != nil {
+	if h.selfSigned() {
+		m["certificate"] = h.certs.file()
+	} else if h.TLS != nil {
 		m["certificate"] = FromDisk(h.TLS.Cert)
 	}
 	// check if it is overwritten by an endpoint
@@ -81,6 +124,9 @@ func (h *Webhook) getFiles() map[string]File {
 			m["certificate"] = FromDisk(h.Endpoint.Cert)
 		}
 	}
+	if h.shared != nil {
+		m["certificate"] = h.shared.file()
+	}
 	return m
 }
 
@@ -104,7 +150,10 @@ func (h *Webhook) getParams() map[string]string {
 		params["secret_token"] = h.SecretToken
 	}
 
-	if h.TLS != nil {
+	if h.selfSigned() {
+		_, port, _ := net.SplitHostPort(h.Listen)
+		params["url"] = "https://" + net.JoinHostPort(h.TLS.SelfSigned.Host, port)
+	} else if h.TLS != nil {
 		params["url"] = "https://" + h.Listen
 	} else {
 		// this will not work with telegram, they want TLS
@@ -120,6 +169,15 @@ func (h *Webhook) getParams() map[string]string {
 }
 
 func (h *Webhook) Poll(b *Bot, dest chan Update, stop chan struct{}) {
+	if h.selfSigned() {
+		if _, err := h.renewCert(); err != nil {
+			b.OnError(err, nil)
+			close(stop)
+			return
+		}
+		go h.rotateCert(b, stop)
+	}
+
 	// by default, the set webhook method will be called, to ignore it, set IgnoreSetWebhook to true
 	if !h.IgnoreSetWebhook {
 		if err := b.SetWebhook(h); err != nil {
@@ -130,8 +188,8 @@ func (h *Webhook) Poll(b *Bot, dest chan Update, stop chan struct{}) {
 	}
 
 	// store the variables so the HTTP-handler can use 'em
-	h.dest = dest
-	h.bot = b
+	h.attach(b, dest)
+	defer h.attach(nil, nil)
 
 	if h.Listen == "" {
 		<-stop
@@ -148,27 +206,328 @@ func (h *Webhook) Poll(b *Bot, dest chan Update, stop chan struct{}) {
 		s.Shutdown(context.Background())
 	}(stop)
 
-	if h.TLS != nil {
+	if h.selfSigned() {
+		s.TLSConfig = &tls.Config{GetCertificate: h.certs.getCertificate}
+		s.ListenAndServeTLS("", "")
+	} else if h.TLS != nil {
 		s.ListenAndServeTLS(h.TLS.Cert, h.TLS.Key)
 	} else {
 		s.ListenAndServe()
 	}
 }
 
-// The handler simply reads the update from the body of the requests
-// and writes them to the update channel.
+// attach sets the bot and the updates channel the webhook serves,
+// nil ones detach it.
+func (h *Webhook) attach(b *Bot, dest chan<- Update) {
+	h.mu.Lock()
+	h.bot, h.dest = b, dest
+	h.mu.Unlock()
+}
+
+// target returns the attached bot and its updates channel.
+func (h *Webhook) target() (*Bot, chan<- Update) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.bot, h.dest
+}
+
+// ServeHTTP checks the request method, the remote address (if CheckIP
+// is set) and the secret token, reads the update limited by MaxBodySize
+// and writes it to the update channel, waiting up to EnqueueTimeout.
+// Rejected requests are answered with the matching status code and
+// counted in Stats. Until the webhook is polled by a bot, the requests
+// are answered with 503, so Telegram retries them later.
 func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, dest := h.target()
+	if dest == nil {
+		w.Header().Set("Retry-After", "1")
+		h.reject(w, nil, &h.stats.NotReady, "not_ready", http.StatusServiceUnavailable,
+			fmt.Errorf("telebot: webhook isn't polled yet"))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		h.reject(w, b, &h.stats.BadMethod, "bad_method", http.StatusMethodNotAllowed,
+			fmt.Errorf("telebot: unexpected webhook request method %s", r.Method))
+		return
+	}
+
+	if h.CheckIP && !isTelegramAddr(r.RemoteAddr) {
+		h.reject(w, b, &h.stats.BadIP, "bad_ip", http.StatusForbidden,
+			fmt.Errorf("telebot: webhook request from unknown address %s", r.RemoteAddr))
+		return
+	}
+
 	if h.SecretToken != "" && r.Header.Get("X-Telegram-Bot-Api-Secret-Token") != h.SecretToken {
-		h.bot.debug(fmt.Errorf("invalid secret token in request"))
+		h.reject(w, b, &h.stats.BadToken, "bad_token", http.StatusUnauthorized,
+			fmt.Errorf("telebot: invalid secret token in request"))
+		return
+	}
+
+	limit := h.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultWebhookBodySize
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		h.reject(w, b, &h.stats.BadUpdate, "bad_update", http.StatusBadRequest,
+			fmt.Errorf("telebot: cannot read update: %v", err))
+		return
+	}
+	if int64(len(data)) > limit {
+		h.reject(w, b, &h.stats.TooLarge, "too_large", http.StatusRequestEntityTooLarge,
+			fmt.Errorf("telebot: update exceeds %d bytes", limit))
 		return
 	}
 
 	var update Update
-	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
-		h.bot.debug(fmt.Errorf("cannot decode update: %v", err))
+	if err := b.decodeUpdate(data, &update); err != nil {
+		h.reject(w, b, &h.stats.BadUpdate, "bad_update", http.StatusBadRequest,
+			fmt.Errorf("telebot: cannot decode update: %v", err))
+		return
+	}
+
+	var reply *webhookReply
+	if h.ReplyTimeout > 0 {
+		reply = newWebhookReply()
+		update.reply = reply
+	}
+
+	if !h.enqueue(dest, update) {
+		w.Header().Set("Retry-After", "1")
+		h.reject(w, b, &h.stats.QueueFull, "queue_full", http.StatusServiceUnavailable,
+			fmt.Errorf("telebot: updates channel is full, update %d is rejected", update.ID))
+		return
+	}
+	b.meter().QueueLength(len(dest))
+
+	if reply == nil {
+		return
+	}
+
+	timer := time.NewTimer(h.ReplyTimeout)
+	defer timer.Stop()
+
+	select {
+	case data = <-reply.data:
+	case <-timer.C:
+		if !reply.release() {
+			// claimed right before the timeout,
+			// the payload is on its way
+			data = <-reply.data
+		} else {
+			data = nil
+		}
+	}
+
+	if data != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
+
+// enqueue writes the update to the updates channel, waiting
+// for EnqueueTimeout at most if it's set.
+func (h *Webhook) enqueue(dest chan<- Update, u Update) bool {
+	if h.EnqueueTimeout <= 0 {
+		dest <- u
+		return true
+	}
+
+	timer := time.NewTimer(h.EnqueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case dest <- u:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (h *Webhook) reject(w http.ResponseWriter, b *Bot, counter *int64, reason string, code int, err error) {
+	atomic.AddInt64(counter, 1)
+	if b != nil {
+		b.meter().WebhookRejected(reason)
+		b.debug(err)
+	}
+	http.Error(w, http.StatusText(code), code)
+}
+
+// Stats returns the counters of the requests rejected by the webhook.
+func (h *Webhook) Stats() WebhookStats {
+	return WebhookStats{
+		BadMethod: atomic.LoadInt64(&h.stats.BadMethod),
+		BadIP:     atomic.LoadInt64(&h.stats.BadIP),
+		BadToken:  atomic.LoadInt64(&h.stats.BadToken),
+		TooLarge:  atomic.LoadInt64(&h.stats.TooLarge),
+		BadUpdate: atomic.LoadInt64(&h.stats.BadUpdate),
+		QueueFull: atomic.LoadInt64(&h.stats.QueueFull),
+		NotReady:  atomic.LoadInt64(&h.stats.NotReady),
+	}
+}
+
+// WebhookStats holds the counters of the rejected webhook requests.
+type WebhookStats struct {
+	BadMethod int64 // not a POST request
+	BadIP     int64 // not from the Telegram subnets, see CheckIP
+	BadToken  int64 // wrong secret token
+	TooLarge  int64 // body exceeds MaxBodySize
+	BadUpdate int64 // malformed body
+	QueueFull int64 // not enqueued within EnqueueTimeout
+	NotReady  int64 // no bot polls the webhook yet
+}
+
+// TelegramSubnets are the published IP ranges Telegram
+// sends webhook requests from. Used if Webhook.CheckIP is set.
+var TelegramSubnets = []string{
+	"149.154.160.0/20",
+	"91.108.4.0/22",
+}
+
+func isTelegramAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, s := range TelegramSubnets {
+		_, subnet, err := net.ParseCIDR(s)
+		if err == nil && subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookReply is a slot for the single method call
+// that can be answered in the webhook response.
+type webhookReply struct {
+	mu    sync.Mutex
+	state int  // 0 - free, 1 - claimed, 2 - released
+	held  bool // a handler is run for the update
+	data  chan []byte
+}
+
+func newWebhookReply() *webhookReply {
+	return &webhookReply{data: make(chan []byte, 1)}
+}
+
+// claim takes the slot. The caller must then call write.
+func (r *webhookReply) claim() bool {
+	if r == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state != 0 {
+		return false
+	}
+	r.state = 1
+	return true
+}
+
+// hold marks the slot as used by the handler,
+// which releases it once it's done.
+func (r *webhookReply) hold() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.held = true
+	r.mu.Unlock()
+}
+
+// releaseIdle releases the slot unless a handler holds it.
+func (r *webhookReply) releaseIdle() {
+	if r == nil {
 		return
 	}
-	h.dest <- update
+
+	r.mu.Lock()
+	held := r.held
+	r.mu.Unlock()
+
+	if !held {
+		r.release()
+	}
+}
+
+// release closes the slot if it's still free. Returns false
+// if it has already been claimed, so the data is to be written.
+func (r *webhookReply) release() bool {
+	if r == nil {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.state {
+	case 0:
+		r.state = 2
+		r.data <- nil
+		return true
+	case 1:
+		return false
+	}
+	return true
+}
+
+// write sends the method with its payload to the webhook response.
+func (r *webhookReply) write(method string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		r.data <- nil
+		return wrapError(err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		r.data <- nil
+		return wrapError(err)
+	}
+	m["method"] = method
+
+	data, err = json.Marshal(m)
+	if err != nil {
+		r.data <- nil
+		return wrapError(err)
+	}
+
+	r.data <- data
+	return nil
+}
+
+func (b *Bot) replyText(r *webhookReply, to Recipient, text string, opt *SendOptions) error {
+	params := map[string]string{
+		"chat_id": to.Recipient(),
+		"text":    text,
+	}
+	b.embedSendOptions(params, opt)
+
+	return r.write("sendMessage", params)
+}
+
+func (b *Bot) replyRespond(r *webhookReply, c *Callback, resp ...*CallbackResponse) error {
+	var cr *CallbackResponse
+	if resp == nil {
+		cr = &CallbackResponse{}
+	} else {
+		cr = resp[0]
+	}
+
+	cr.CallbackID = c.ID
+	return r.write("answerCallbackQuery", cr)
 }
 
 // Webhook returns the current webhook status.
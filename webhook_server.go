@@ -0,0 +1,250 @@
+package telebot
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookServer serves webhooks of multiple bots on a single listener.
+// Incoming requests are routed to the matching Webhook by the request
+// path and, if no path matches, by the secret token header.
+//
+// Each webhook is registered with a unique path, which is appended to
+// the public URL of the server when calling setWebhook. The public URL is
+// Endpoint.PublicURL, if set, or is made of the TLS.SelfSigned host or
+// the Listen address otherwise. If TLS is filled, the server opens
+// a secure port, and its certificate is uploaded for every bot unless
+// Endpoint says otherwise, just like with Webhook.
+//
+// You can also leave the Listen field empty and mount the server into
+// an existing http.ServeMux, as it implements http.Handler:
+//
+//	s := &tele.WebhookServer{
+//		Endpoint: &tele.WebhookEndpoint{PublicURL: "https://example.com/bots"},
+//	}
+//	mux.Handle("/bots/", http.StripPrefix("/bots", s))
+//
+//	hook := &tele.Webhook{SecretToken: "..."}
+//	if err := s.Add("/first", hook); err != nil {
+//		return err
+//	}
+//	b, err := tele.NewBot(tele.Settings{Token: "...", Poller: hook})
+type WebhookServer struct {
+	Listen   string
+	TLS      *WebhookTLS
+	Endpoint *WebhookEndpoint
+
+	mu    sync.RWMutex
+	hooks map[string]*Webhook
+	srv   *http.Server
+	certs certStore
+}
+
+// Add registers the webhook under the given path. The path must be unique
+// within the server. The webhook is configured to not open its own listener,
+// and unless it has its own Endpoint, to use the public URL and the
+// certificate of the server.
+func (s *WebhookServer) Add(path string, h *Webhook) error {
+	path = "/" + strings.Trim(path, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hooks == nil {
+		s.hooks = make(map[string]*Webhook)
+	}
+	if _, ok := s.hooks[path]; ok {
+		return fmt.Errorf("telebot: webhook path %s is already registered", path)
+	}
+
+	if h.Endpoint == nil {
+		url, err := s.publicURL()
+		if err != nil {
+			return err
+		}
+
+		h.Endpoint = &WebhookEndpoint{PublicURL: url + path}
+		switch {
+		case s.Endpoint != nil:
+			h.Endpoint.Cert = s.Endpoint.Cert
+		case s.selfSigned():
+			if _, err := s.certs.renew(s.TLS.SelfSigned); err != nil {
+				return err
+			}
+			h.shared = &s.certs
+		case s.TLS != nil:
+			h.Endpoint.Cert = s.TLS.Cert
+		}
+	}
+
+	h.Listen = ""
+
+	s.hooks[path] = h
+	return nil
+}
+
+// Remove unregisters the webhook with the given path.
+func (s *WebhookServer) Remove(path string) {
+	path = "/" + strings.Trim(path, "/")
+
+	s.mu.Lock()
+	delete(s.hooks, path)
+	s.mu.Unlock()
+}
+
+// Webhook returns the webhook registered under the given path.
+func (s *WebhookServer) Webhook(path string) (*Webhook, bool) {
+	path = "/" + strings.Trim(path, "/")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	h, ok := s.hooks[path]
+	return h, ok
+}
+
+// ServeHTTP routes the request to the matching webhook.
+func (s *WebhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := s.match(r)
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	h.ServeHTTP(w, r)
+}
+
+func (s *WebhookServer) match(r *http.Request) *Webhook {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if h, ok := s.hooks["/"+strings.Trim(r.URL.Path, "/")]; ok {
+		return h
+	}
+
+	token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+	if token == "" {
+		return nil
+	}
+	for _, h := range s.hooks {
+		if h.SecretToken == token {
+			return h
+		}
+	}
+	return nil
+}
+
+// publicURL returns the URL the paths of the webhooks are appended to.
+func (s *WebhookServer) publicURL() (string, error) {
+	if s.Endpoint != nil && s.Endpoint.PublicURL != "" {
+		return strings.TrimRight(s.Endpoint.PublicURL, "/"), nil
+	}
+
+	host, port, err := net.SplitHostPort(s.Listen)
+	if s.selfSigned() && s.TLS.SelfSigned.Host != "" {
+		if port == "" {
+			return "https://" + s.TLS.SelfSigned.Host, nil
+		}
+		return "https://" + net.JoinHostPort(s.TLS.SelfSigned.Host, port), nil
+	}
+	if err != nil || host == "" {
+		return "", fmt.Errorf("telebot: cannot make public URL of the webhook server from %q, set Endpoint", s.Listen)
+	}
+
+	if s.TLS != nil {
+		return "https://" + s.Listen, nil
+	}
+	return "http://" + s.Listen, nil
+}
+
+// selfSigned reports whether the server generates its own certificate.
+func (s *WebhookServer) selfSigned() bool {
+	return s.TLS != nil && s.TLS.SelfSigned != nil
+}
+
+// rotateCert periodically renews the certificate of the server
+// and uploads it to Telegram for every bot using it.
+func (s *WebhookServer) rotateCert(stop chan struct{}) {
+	ticker := time.NewTicker(selfSignedCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := s.certs.renew(s.TLS.SelfSigned)
+		if !renewed && err == nil {
+			continue
+		}
+
+		s.mu.RLock()
+		for _, h := range s.hooks {
+			b, _ := h.target()
+			if h.shared != &s.certs || b == nil {
+				continue
+			}
+			if err != nil {
+				b.OnError(err, nil)
+			} else if !h.IgnoreSetWebhook {
+				if err := b.SetWebhook(h); err != nil {
+					b.OnError(err, nil)
+				}
+			}
+		}
+		s.mu.RUnlock()
+	}
+}
+
+// Start opens the listener and serves the registered webhooks.
+// It blocks until the server is stopped.
+func (s *WebhookServer) Start() error {
+	s.mu.Lock()
+	s.srv = &http.Server{
+		Addr:    s.Listen,
+		Handler: s,
+	}
+	srv := s.srv
+	s.mu.Unlock()
+
+	var err error
+	if s.selfSigned() {
+		if _, err := s.certs.renew(s.TLS.SelfSigned); err != nil {
+			return err
+		}
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go s.rotateCert(stop)
+
+		srv.TLSConfig = &tls.Config{GetCertificate: s.certs.getCertificate}
+		err = srv.ListenAndServeTLS("", "")
+	} else if s.TLS != nil {
+		err = srv.ListenAndServeTLS(s.TLS.Cert, s.TLS.Key)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Stop gracefully shuts the server down.
+func (s *WebhookServer) Stop() error {
+	s.mu.RLock()
+	srv := s.srv
+	s.mu.RUnlock()
+
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(context.Background())
+}
@@ -2,8 +2,12 @@ package telebot
 
 import (
 	"bytes"
+	"crypto/x509"
 	"encoding/json"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -81,8 +85,7 @@ func TestWebhook_ServeHTTP(t *testing.T) {
 	require.NoError(t, err)
 
 	dest := make(chan Update, 1)
-	webhook.dest = dest
-	webhook.bot = b
+	webhook.attach(b, dest)
 
 	t.Run("valid update", func(t *testing.T) {
 		update := Update{
@@ -158,3 +161,259 @@ func TestWebhook_ServeHTTP(t *testing.T) {
 		webhook.SecretToken = ""
 	})
 }
+
+func TestWebhook_Reply(t *testing.T) {
+	pref := defaultSettings()
+	pref.Offline = true
+	pref.Synchronous = true
+
+	b, err := NewBot(pref)
+	require.NoError(t, err)
+
+	b.Handle(OnCallback, func(c Context) error {
+		return c.RespondText("done")
+	})
+	b.Handle(OnQuery, func(c Context) error {
+		return nil
+	})
+
+	dest := make(chan Update, 1)
+	webhook := &Webhook{ReplyTimeout: time.Second}
+	webhook.attach(b, dest)
+
+	serve := func(u Update) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(u)
+		req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		done := make(chan struct{})
+		go func() {
+			webhook.ServeHTTP(w, req)
+			close(done)
+		}()
+
+		b.ProcessUpdate(<-dest)
+		<-done
+		return w
+	}
+
+	t.Run("callback", func(t *testing.T) {
+		w := serve(Update{ID: 1, Callback: &Callback{ID: "42"}})
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "answerCallbackQuery", resp["method"])
+		assert.Equal(t, "42", resp["callback_query_id"])
+		assert.Equal(t, "done", resp["text"])
+	})
+
+	t.Run("no reply", func(t *testing.T) {
+		start := time.Now()
+		w := serve(Update{ID: 2, Query: &Query{ID: "1"}})
+
+		assert.Empty(t, w.Body.Bytes())
+		assert.Less(t, time.Since(start), webhook.ReplyTimeout)
+	})
+
+	t.Run("unhandled", func(t *testing.T) {
+		start := time.Now()
+		w := serve(Update{ID: 3, ShippingQuery: &ShippingQuery{ID: "1"}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.Bytes())
+		assert.Less(t, time.Since(start), webhook.ReplyTimeout)
+	})
+}
+
+func TestWebhookServer(t *testing.T) {
+	s := &WebhookServer{
+		Endpoint: &WebhookEndpoint{PublicURL: "https://example.com/bots/"},
+	}
+
+	pref := defaultSettings()
+	pref.Offline = true
+
+	b, err := NewBot(pref)
+	require.NoError(t, err)
+
+	first, second := &Webhook{Listen: ":443"}, &Webhook{SecretToken: "second"}
+	require.NoError(t, s.Add("first", first))
+	require.NoError(t, s.Add("/second/", second))
+	assert.Error(t, s.Add("/first", &Webhook{}))
+
+	assert.Empty(t, first.Listen)
+	assert.Equal(t, "https://example.com/bots/first", first.getParams()["url"])
+	assert.NotContains(t, first.getFiles(), "certificate")
+
+	dests := map[*Webhook]chan Update{
+		first:  make(chan Update, 1),
+		second: make(chan Update, 1),
+	}
+	for h, dest := range dests {
+		h.attach(b, dest)
+	}
+
+	body, _ := json.Marshal(Update{ID: 1})
+
+	req := httptest.NewRequest("POST", "/first", bytes.NewReader(body))
+	s.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 1, (<-dests[first]).ID)
+
+	req = httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("X-Telegram-Bot-Api-Secret-Token", "second")
+	s.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, 1, (<-dests[second]).ID)
+
+	s.Remove("first")
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest("POST", "/first", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	t.Run("not polled", func(t *testing.T) {
+		h := &Webhook{}
+		require.NoError(t, s.Add("/third", h))
+
+		serve := func() *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, httptest.NewRequest("POST", "/third", bytes.NewReader(body)))
+			return w
+		}
+
+		w := serve()
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Equal(t, int64(1), h.Stats().NotReady)
+
+		// polled while the server is serving
+		dest := make(chan Update, 1)
+		go h.attach(b, dest)
+		assert.Eventually(t, func() bool {
+			return serve().Code == http.StatusOK
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, 1, (<-dest).ID)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		s := &WebhookServer{
+			Listen: "bots.example.com:8443",
+			TLS:    &WebhookTLS{Cert: "cert.pem", Key: "key.pem"},
+		}
+
+		h := &Webhook{}
+		require.NoError(t, s.Add("/first", h))
+		assert.Equal(t, "https://bots.example.com:8443/first", h.getParams()["url"])
+		assert.Equal(t, FromDisk("cert.pem"), h.getFiles()["certificate"])
+
+		assert.Error(t, (&WebhookServer{Listen: ":8443"}).Add("/first", &Webhook{}))
+	})
+
+	t.Run("self_signed", func(t *testing.T) {
+		s := &WebhookServer{
+			Listen: ":8443",
+			TLS:    &WebhookTLS{SelfSigned: &WebhookSelfSigned{Host: "1.2.3.4"}},
+		}
+
+		first, second := &Webhook{}, &Webhook{}
+		require.NoError(t, s.Add("/first", first))
+		require.NoError(t, s.Add("/second", second))
+		assert.Equal(t, "https://1.2.3.4:8443/first", first.getParams()["url"])
+
+		cert := first.getFiles()["certificate"]
+		assert.NotZero(t, cert.FileSize)
+		assert.Equal(t, cert.FileSize, second.getFiles()["certificate"].FileSize)
+		assert.Same(t, first.shared, second.shared)
+	})
+}
+
+func TestWebhook_Reject(t *testing.T) {
+	pref := defaultSettings()
+	pref.Offline = true
+
+	b, err := NewBot(pref)
+	require.NoError(t, err)
+
+	webhook := &Webhook{
+		SecretToken:    "secret",
+		MaxBodySize:    64,
+		EnqueueTimeout: 10 * time.Millisecond,
+		CheckIP:        true,
+	}
+	webhook.attach(b, make(chan Update))
+
+	serve := func(method, addr, token, body string) int {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.RemoteAddr = addr
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", token)
+
+		w := httptest.NewRecorder()
+		webhook.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	const tgAddr = "149.154.167.220:443"
+
+	assert.Equal(t, http.StatusMethodNotAllowed, serve("GET", tgAddr, "secret", ""))
+	assert.Equal(t, http.StatusForbidden, serve("POST", "1.2.3.4:443", "secret", "{}"))
+	assert.Equal(t, http.StatusUnauthorized, serve("POST", tgAddr, "wrong", "{}"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve("POST", tgAddr, "secret", strings.Repeat(" ", 65)))
+	assert.Equal(t, http.StatusBadRequest, serve("POST", tgAddr, "secret", "invalid json"))
+	assert.Equal(t, http.StatusServiceUnavailable, serve("POST", tgAddr, "secret", `{"update_id":1}`))
+
+	assert.Equal(t, WebhookStats{
+		BadMethod: 1,
+		BadIP:     1,
+		BadToken:  1,
+		TooLarge:  1,
+		BadUpdate: 1,
+		QueueFull: 1,
+	}, webhook.Stats())
+}
+
+func TestWebhook_SelfSigned(t *testing.T) {
+	dir := t.TempDir()
+
+	webhook := &Webhook{
+		Listen: "0.0.0.0:8443",
+		TLS: &WebhookTLS{SelfSigned: &WebhookSelfSigned{
+			Host: "203.0.113.1",
+			Dir:  dir,
+		}},
+	}
+
+	renewed, err := webhook.renewCert()
+	require.NoError(t, err)
+	assert.True(t, renewed)
+
+	c := webhook.currentCert()
+	require.NotNil(t, c)
+
+	leaf, err := x509.ParseCertificate(c.cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.1", leaf.Subject.CommonName)
+	assert.Equal(t, "203.0.113.1", leaf.IPAddresses[0].String())
+
+	assert.Equal(t, "https://203.0.113.1:8443", webhook.getParams()["url"])
+
+	file := webhook.getFiles()["certificate"]
+	r, err := file.opener()()
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, c.certPEM, data)
+
+	renewed, err = webhook.renewCert()
+	require.NoError(t, err)
+	assert.False(t, renewed)
+
+	// the stored certificate is reused
+	stored, err := webhook.TLS.SelfSigned.obtain()
+	require.NoError(t, err)
+	assert.Equal(t, c.certPEM, stored.certPEM)
+
+	// and rotated once it's about to expire
+	webhook.TLS.SelfSigned.RenewBefore = 2 * 365 * 24 * time.Hour
+	renewed, err = webhook.renewCert()
+	require.NoError(t, err)
+	assert.True(t, renewed)
+	assert.NotEqual(t, c.certPEM, webhook.currentCert().certPEM)
+}
@@ -0,0 +1,263 @@
+package telebot
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// A WebhookSelfSigned makes the webhook generate a self-signed certificate
+// for the given public IP or hostname at startup. The certificate is served
+// by the TLS listener, uploaded to Telegram within setWebhook and rotated
+// RenewBefore its expiry.
+//
+// If Dir is empty, the certificate is kept in memory only and a new one is
+// generated on every start. Otherwise, it's stored in the directory as
+// cert.pem and key.pem and reused until it's about to expire.
+type WebhookSelfSigned struct {
+	Host string `json:"host"`
+	Dir  string `json:"dir"`
+
+	// Validity is the certificate lifetime, defaulted to a year.
+	Validity time.Duration `json:"validity"`
+
+	// RenewBefore is how long before expiry the certificate
+	// is rotated, defaulted to 30 days.
+	RenewBefore time.Duration `json:"renew_before"`
+}
+
+const (
+	selfSignedCertFile = "cert.pem"
+	selfSignedKeyFile  = "key.pem"
+)
+
+// selfSignedCheckInterval is how often the certificate expiry is checked.
+var selfSignedCheckInterval = time.Hour
+
+type webhookCert struct {
+	cert     tls.Certificate
+	certPEM  []byte
+	notAfter time.Time
+}
+
+func (s *WebhookSelfSigned) validity() time.Duration {
+	if s.Validity <= 0 {
+		return 365 * 24 * time.Hour
+	}
+	return s.Validity
+}
+
+func (s *WebhookSelfSigned) renewBefore() time.Duration {
+	if s.RenewBefore <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return s.RenewBefore
+}
+
+func (s *WebhookSelfSigned) expiring(c *webhookCert) bool {
+	return c == nil || time.Until(c.notAfter) < s.renewBefore()
+}
+
+// obtain returns the stored certificate if it's still fresh,
+// otherwise generates and stores a new one.
+func (s *WebhookSelfSigned) obtain() (*webhookCert, error) {
+	if s.Dir != "" {
+		if c, err := s.load(); err == nil && !s.expiring(c) {
+			return c, nil
+		}
+	}
+
+	c, keyPEM, err := s.generate()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Dir != "" {
+		if err := s.store(c.certPEM, keyPEM); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (s *WebhookSelfSigned) generate() (*webhookCert, []byte, error) {
+	if s.Host == "" {
+		return nil, nil, fmt.Errorf("telebot: self-signed certificate host is empty")
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, wrapError(err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, wrapError(err)
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: s.Host},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(s.validity()),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if ip := net.ParseIP(s.Host); ip != nil {
+		tmpl.IPAddresses = []net.IP{ip}
+	} else {
+		tmpl.DNSNames = []string{s.Host}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, wrapError(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	c, err := parseWebhookCert(certPEM, keyPEM)
+	return c, keyPEM, err
+}
+
+func (s *WebhookSelfSigned) load() (*webhookCert, error) {
+	certPEM, err := ioutil.ReadFile(filepath.Join(s.Dir, selfSignedCertFile))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := ioutil.ReadFile(filepath.Join(s.Dir, selfSignedKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	return parseWebhookCert(certPEM, keyPEM)
+}
+
+func (s *WebhookSelfSigned) store(certPEM, keyPEM []byte) error {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return wrapError(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.Dir, selfSignedKeyFile), keyPEM, 0600); err != nil {
+		return wrapError(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.Dir, selfSignedCertFile), certPEM, 0644); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
+func parseWebhookCert(certPEM, keyPEM []byte) (*webhookCert, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &webhookCert{
+		cert:     cert,
+		certPEM:  certPEM,
+		notAfter: leaf.NotAfter,
+	}, nil
+}
+
+// selfSigned reports whether the webhook generates its own certificate.
+func (h *Webhook) selfSigned() bool {
+	return h.TLS != nil && h.TLS.SelfSigned != nil
+}
+
+// certStore holds the self-signed certificate being served.
+type certStore struct {
+	mu   sync.RWMutex
+	cert *webhookCert
+}
+
+func (cs *certStore) current() *webhookCert {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.cert
+}
+
+// renew obtains a new self-signed certificate if the current one
+// is missing or about to expire. Reports whether it was replaced.
+func (cs *certStore) renew(s *WebhookSelfSigned) (bool, error) {
+	if !s.expiring(cs.current()) {
+		return false, nil
+	}
+
+	c, err := s.obtain()
+	if err != nil {
+		return false, err
+	}
+
+	cs.mu.Lock()
+	cs.cert = c
+	cs.mu.Unlock()
+	return true, nil
+}
+
+func (cs *certStore) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c := cs.current()
+	if c == nil {
+		return nil, fmt.Errorf("telebot: webhook certificate is not ready")
+	}
+	return &c.cert, nil
+}
+
+func (cs *certStore) file() File {
+	c := cs.current()
+	if c == nil {
+		return File{}
+	}
+	return FromBytes(selfSignedCertFile, c.certPEM)
+}
+
+func (h *Webhook) currentCert() *webhookCert {
+	return h.certs.current()
+}
+
+func (h *Webhook) renewCert() (bool, error) {
+	return h.certs.renew(h.TLS.SelfSigned)
+}
+
+// rotateCert periodically renews the certificate and uploads it to Telegram.
+func (h *Webhook) rotateCert(b *Bot, stop chan struct{}) {
+	ticker := time.NewTicker(selfSignedCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := h.renewCert()
+		if err != nil {
+			b.OnError(err, nil)
+			continue
+		}
+		if renewed && !h.IgnoreSetWebhook {
+			if err := b.SetWebhook(h); err != nil {
+				b.OnError(err, nil)
+			}
+		}
+	}
+}